@@ -0,0 +1,256 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LoadRow — рядок стандартної таблиці розрахунку електричних навантажень.
+// Для підсумкових рядків (Total) заповнені також nе, Kр, Pр, Qр, Sр, Iр.
+type LoadRow struct {
+	Name    string
+	N       float64
+	Pn      float64
+	NPn     float64
+	Kv      float64
+	Tg      float64
+	NPnKv   float64
+	NPnKvTg float64
+	NPn2    float64
+
+	Total bool
+	Ne    float64
+	Kp    float64
+	Pp    float64
+	Qp    float64
+	Sp    float64
+	Ip    float64
+
+	// Aggregate — рядок зі зведеними даними, без n, Pн, tgφ.
+	Aggregate bool
+}
+
+type LoadTable struct {
+	Rows []LoadRow
+}
+
+// loadTotals — результати розрахунку для підсумкового рядка.
+type loadTotals struct {
+	Kv, Ne, Kp, Pp, Qp, Sp, Ip float64
+}
+
+// Решта ЕП ШР з варіанту завдання. Добутки записані явно — це ті самі
+// числа, що зашиті в getGroupUtilizationFactor, getEffectiveAmount і т.д.
+var shrFixedRows = []LoadRow{
+	{Name: "Свердлильний верстат", N: 2, Pn: 14, NPn: 28, Kv: 0.12, Tg: 1, NPnKv: 3.36, NPnKvTg: 3.36, NPn2: 392},
+	{Name: "Фугувальний верстат", N: 4, Pn: 42, NPn: 168, Kv: 0.15, Tg: 1.33, NPnKv: 25.2, NPnKvTg: 33.5, NPn2: 7056},
+	{Name: "Прес", N: 1, Pn: 20, NPn: 20, Kv: 0.5, Tg: 0.75, NPnKv: 10, NPnKvTg: 7.5, NPn2: 400},
+	{Name: "Фрезерний верстат", N: 2, Pn: 32, NPn: 64, Kv: 0.2, Tg: 1, NPnKv: 12.8, NPnKvTg: 12.8, NPn2: 2048},
+	{Name: "Вентилятор", N: 1, Pn: 20, NPn: 20, Kv: 0.65, Tg: 0.73, NPnKv: 13, NPnKvTg: 9.5, NPn2: 400},
+}
+
+// Інші ШР та крупні ЕП цеху — у формулах вони є лише сумами,
+// тому в таблиці показуємо один зведений рядок.
+var workshopFixedRow = LoadRow{
+	Name:      "Інші ШР та крупні ЕП цеху",
+	NPn:       456*2 + 465 + 200 + 240,
+	NPnKv:     95.1*3 + 40 + 192,
+	NPn2:      14792*3 + 20000 + 28800,
+	Aggregate: true,
+}
+
+func userLoadRow(row EPRow, params Params) LoadRow {
+	nPn := multiplicationNandP(row.N, row.Pn)
+	nPnKv := multiplicationK(nPn, row.Kv)
+	return LoadRow{
+		Name:    row.Name,
+		N:       row.N,
+		Pn:      row.Pn,
+		NPn:     nPn,
+		Kv:      row.Kv,
+		Tg:      row.Tg,
+		NPnKv:   nPnKv,
+		NPnKvTg: params.reactive(row.Tg, nPnKv),
+		NPn2:    sumMultiplicationNandPInSquare(row.N, row.Pn),
+	}
+}
+
+func totalRow(name string, rows []LoadRow, t loadTotals) LoadRow {
+	total := LoadRow{Name: name, Total: true}
+	for _, r := range rows {
+		total.N += r.N
+		total.NPn += r.NPn
+		total.NPnKv += r.NPnKv
+		total.NPnKvTg += r.NPnKvTg
+		total.NPn2 += r.NPn2
+	}
+	total.Kv = t.Kv
+	total.Ne = t.Ne
+	total.Kp = t.Kp
+	total.Pp = t.Pp
+	total.Qp = t.Qp
+	total.Sp = t.Sp
+	total.Ip = t.Ip
+	return total
+}
+
+func buildLoadTable(res *Results, shr, workshop loadTotals) LoadTable {
+	var shrRows []LoadRow
+	for _, row := range []EPRow{res.EP1, res.EP2, res.EP3} {
+		shrRows = append(shrRows, userLoadRow(row, res.Params))
+	}
+	shrRows = append(shrRows, shrFixedRows...)
+	shrTotal := totalRow("Всього на ШР1=ШР2=ШР3", shrRows, shr)
+
+	// Кількість ЕП інших ШР та Qр шин ТП через tgφ у формулах не
+	// рахуються, тому n та n·Pн·Kв·tgφ для цеху в таблиці не підсумовуємо.
+	workshopTotal := totalRow("Всього на шинах 0,38 кВ ТП", []LoadRow{
+		{NPn: shrTotal.NPn, NPnKv: shrTotal.NPnKv, NPn2: shrTotal.NPn2},
+		workshopFixedRow,
+	}, workshop)
+
+	rows := append(shrRows, shrTotal, workshopFixedRow, workshopTotal)
+	return LoadTable{Rows: rows}
+}
+
+// ----- подання таблиці -----
+
+// loadColumn описує колонку звіту: заголовок, точність і значення.
+// Одна й та сама специфікація використовується для XLSX та HTML.
+type loadColumn struct {
+	Title    string
+	Decimals int
+	Value    func(r LoadRow) (float64, bool)
+}
+
+func perRow(f func(r LoadRow) float64) func(r LoadRow) (float64, bool) {
+	return func(r LoadRow) (float64, bool) { return f(r), !r.Total && !r.Aggregate }
+}
+
+func always(f func(r LoadRow) float64) func(r LoadRow) (float64, bool) {
+	return func(r LoadRow) (float64, bool) { return f(r), true }
+}
+
+func withoutAggregate(f func(r LoadRow) float64) func(r LoadRow) (float64, bool) {
+	return func(r LoadRow) (float64, bool) { return f(r), !r.Aggregate }
+}
+
+func totalOnly(f func(r LoadRow) float64) func(r LoadRow) (float64, bool) {
+	return func(r LoadRow) (float64, bool) { return f(r), r.Total }
+}
+
+var loadColumns = []loadColumn{
+	{"n", 0, func(r LoadRow) (float64, bool) { return r.N, !r.Aggregate && r.N != 0 }},
+	{"Pн, кВт", 2, perRow(func(r LoadRow) float64 { return r.Pn })},
+	{"n·Pн, кВт", 2, always(func(r LoadRow) float64 { return r.NPn })},
+	{"Kв", 4, withoutAggregate(func(r LoadRow) float64 { return r.Kv })},
+	{"tgφ", 2, perRow(func(r LoadRow) float64 { return r.Tg })},
+	{"n·Pн·Kв, кВт", 2, always(func(r LoadRow) float64 { return r.NPnKv })},
+	{"n·Pн·Kв·tgφ, квар", 2, func(r LoadRow) (float64, bool) { return r.NPnKvTg, !r.Aggregate && r.NPnKvTg != 0 }},
+	{"n·Pн², кВт²", 2, always(func(r LoadRow) float64 { return r.NPn2 })},
+	{"nе", 2, totalOnly(func(r LoadRow) float64 { return r.Ne })},
+	{"Kр", 2, totalOnly(func(r LoadRow) float64 { return r.Kp })},
+	{"Pр, кВт", 2, totalOnly(func(r LoadRow) float64 { return r.Pp })},
+	{"Qр, квар", 2, totalOnly(func(r LoadRow) float64 { return r.Qp })},
+	{"Sр, кВ·А", 3, totalOnly(func(r LoadRow) float64 { return r.Sp })},
+	{"Iр, А", 2, totalOnly(func(r LoadRow) float64 { return r.Ip })},
+}
+
+// ReportView — таблиця, вже відформатована для шаблону report.html.
+type ReportView struct {
+	ProjectName string
+	Headers     []string
+	Rows        []ReportRow
+}
+
+type ReportRow struct {
+	Name  string
+	Cells []string
+	Total bool
+}
+
+func (t LoadTable) View(projectName string) ReportView {
+	v := ReportView{ProjectName: projectName}
+	for _, c := range loadColumns {
+		v.Headers = append(v.Headers, c.Title)
+	}
+	for _, r := range t.Rows {
+		row := ReportRow{Name: r.Name, Total: r.Total}
+		for _, c := range loadColumns {
+			cell := ""
+			if val, ok := c.Value(r); ok {
+				cell = strings.Replace(strconv.FormatFloat(val, 'f', c.Decimals, 64), ".", ",", 1)
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		v.Rows = append(v.Rows, row)
+	}
+	return v
+}
+
+// Sheet — таблиця для XLSX; числа округлюються до тієї ж точності, що й у HTML.
+func (t LoadTable) Sheet(projectName string) xlsxSheet {
+	sheet := xlsxSheet{Name: "Навантаження", ColWidths: []float64{30}}
+
+	title := "Розрахунок електричних навантажень"
+	if projectName != "" {
+		title += ": " + projectName
+	}
+	sheet.Rows = append(sheet.Rows, []xlsxCell{{Str: title, Bold: true}}, nil)
+
+	header := []xlsxCell{{Str: "Найменування ЕП", Bold: true}}
+	for _, c := range loadColumns {
+		header = append(header, xlsxCell{Str: c.Title, Bold: true})
+		sheet.ColWidths = append(sheet.ColWidths, 14)
+	}
+	sheet.Rows = append(sheet.Rows, header)
+
+	for _, r := range t.Rows {
+		row := []xlsxCell{{Str: r.Name, Bold: r.Total}}
+		for _, c := range loadColumns {
+			val, ok := c.Value(r)
+			if !ok {
+				row = append(row, xlsxCell{})
+				continue
+			}
+			row = append(row, xlsxCell{Num: roundTo(val, c.Decimals), IsNum: true, Bold: r.Total})
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet
+}
+
+// ----- обробники -----
+
+func handleReportXLSX(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res := readResults(r)
+	calculate(&res)
+
+	name := res.ProjectName
+	if name == "" {
+		name = "load-table"
+	}
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+fileName(name)+".xlsx\"")
+	if err := writeXLSX(w, res.Table.Sheet(res.ProjectName)); err != nil {
+		log.Println("xlsx error:", err)
+	}
+}
+
+func handleReportPrint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res := readResults(r)
+	calculate(&res)
+	if err := reportTpl.Execute(w, res.Table.View(res.ProjectName)); err != nil {
+		log.Println("template error:", err)
+	}
+}
@@ -11,15 +11,85 @@ import (
 )
 
 type EPRow struct {
-	Name string
-
-	Eta  float64 // ηн (не використовується в формулах — як і в твоєму JS)
-	Cos  float64 // cosφ (не використовується в формулах — як і в твоєму JS)
-	U    float64 // Uн (беремо U тільки з першого рядка, як у JS)
-	N    float64 // n
-	Pn   float64 // Pн
-	Kv   float64 // Kв
-	Tg   float64 // tgφ
+	Name string `json:"name"`
+
+	Eta float64 `json:"eta"` // ηн (не використовується в формулах — як і в твоєму JS)
+	Cos float64 `json:"cos"` // cosφ (не використовується в формулах — як і в твоєму JS)
+	U   float64 `json:"u"`   // Uн (беремо U тільки з першого рядка, як у JS)
+	N   float64 `json:"n"`   // n
+	Pn  float64 `json:"pn"`  // Pн
+	Kv  float64 `json:"kv"`  // Kв
+	Tg  float64 `json:"tg"`  // tgφ
+}
+
+// Params — коефіцієнти, які раніше були зашиті прямо в handleIndex.
+type Params struct {
+	ActivePowerFactor                float64       `json:"activePowerFactor"`                // Kр для ШР1=ШР2=ШР3
+	CoefficientActiveApacityWorkshop float64       `json:"coefficientActiveApacityWorkshop"` // Kр цеху в цілому
+	Precision                        PrecisionMode `json:"precision,omitempty"`
+}
+
+// PrecisionMode — політика округлення проміжних значень.
+//
+// PrecisionLegacy повторює старий JS: n·Pн·Kв·tgφ кожного рядка
+// округлюється до 0,1 (toFixed(1)), а nе для ШР — вгору до цілого.
+// PrecisionExact нічого не округлює до форматування виводу.
+//
+// Розбіжності режимів на прикладі з placeholder-ів форми зафіксовані в
+// main_test.go. Постійні дані варіанту (33,5; 9,5 квар тощо) в обох режимах
+// однакові — це вихідні дані завдання, а не проміжні результати.
+type PrecisionMode string
+
+const (
+	PrecisionLegacy PrecisionMode = "legacy"
+	PrecisionExact  PrecisionMode = "exact"
+)
+
+func parsePrecision(s string) PrecisionMode {
+	if PrecisionMode(s) == PrecisionExact {
+		return PrecisionExact
+	}
+	return PrecisionLegacy
+}
+
+// reactive — n·Pн·Kв·tgφ рядка з урахуванням режиму точності.
+func (p Params) reactive(tg, calculatedMultipK float64) float64 {
+	if p.Precision == PrecisionExact {
+		return tg * calculatedMultipK
+	}
+	return multiplicationTgRounded1(tg, calculatedMultipK)
+}
+
+// effectiveAmount — nе для ШР: у legacy-режимі округлюється вгору.
+func (p Params) effectiveAmount(ne float64) float64 {
+	if p.Precision == PrecisionExact {
+		return ne
+	}
+	return math.Ceil(ne)
+}
+
+func defaultParams() Params {
+	return Params{ActivePowerFactor: 1.25, CoefficientActiveApacityWorkshop: 0.7, Precision: PrecisionLegacy}
+}
+
+// Output — вивід (форматований як у твоєму JS toFixed/ceil)
+type Output struct {
+	GroupUtilizationFactor string `json:"groupUtilizationFactor"`
+	EffectiveAmount        string `json:"effectiveAmount"`
+	ActivePowerFactor      string `json:"activePowerFactor"`
+	CalculatedActiveLoad   string `json:"calculatedActiveLoad"`
+	CalculatedReactiveLoad string `json:"calculatedReactiveLoad"`
+	FullPower              string `json:"fullPower"`
+	GroupCurrent           string `json:"groupCurrent"`
+
+	UtilizationRatesWorkshop     string `json:"utilizationRatesWorkshop"`
+	EffectiveNumberEPWorkshop    string `json:"effectiveNumberEPWorkshop"`
+	CoefficientActiveApacityWork string `json:"coefficientActiveApacityWork"`
+
+	ActiveTireLoad    string `json:"activeTireLoad"`
+	ReactiveTireLoad  string `json:"reactiveTireLoad"`
+	FullPowerTires    string `json:"fullPowerTires"`
+	GroupCurrentTires string `json:"groupCurrentTires"`
 }
 
 type Results struct {
@@ -28,28 +98,25 @@ type Results struct {
 	EP2 EPRow
 	EP3 EPRow
 
+	Params Params
+
 	Submitted bool
 
-	// Вивід (форматований як у твоєму JS toFixed/ceil)
-	GroupUtilizationFactor string
-	EffectiveAmount        string
-	ActivePowerFactor      string
-	CalculatedActiveLoad   string
-	CalculatedReactiveLoad string
-	FullPower              string
-	GroupCurrent           string
-
-	UtilizationRatesWorkshop     string
-	EffectiveNumberEPWorkshop    string
-	CoefficientActiveApacityWork string
-
-	ActiveTireLoad       string
-	ReactiveTireLoad     string
-	FullPowerTires       string
-	GroupCurrentTires    string
+	Output
+
+	// Таблиця розрахунку навантажень для звіту (XLSX / друк)
+	Table LoadTable
+
+	// Збережені проєкти поточного користувача
+	ProjectID   string
+	ProjectName string
+	Projects    []ProjectInfo
+	Message     string
+	Error       string
 }
 
 var tpl = template.Must(template.ParseFiles("templates/index.html"))
+var reportTpl = template.Must(template.ParseFiles("templates/report.html"))
 
 func main() {
 	mux := http.NewServeMux()
@@ -58,6 +125,13 @@ func main() {
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
 
 	mux.HandleFunc("/", handleIndex)
+	mux.HandleFunc("/project/export", handleProjectExport)
+	mux.HandleFunc("/project/import", handleProjectImport)
+	mux.HandleFunc("/project/save", handleProjectSave)
+	mux.HandleFunc("/project/load", handleProjectLoad)
+	mux.HandleFunc("/project/delete", handleProjectDelete)
+	mux.HandleFunc("/report/xlsx", handleReportXLSX)
+	mux.HandleFunc("/report/print", handleReportPrint)
 
 	addr := ":8080"
 	log.Println("Server started on http://localhost" + addr)
@@ -67,92 +141,13 @@ func main() {
 func handleIndex(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		_ = tpl.Execute(w, Results{})
+		render(w, r, Results{Params: defaultParams()})
 		return
 
 	case http.MethodPost:
-		res := Results{Submitted: true}
-
-		res.EP1 = readEPRow(r, "")
-		res.EP2 = readEPRow(r, "-2")
-		res.EP3 = readEPRow(r, "-3")
-
-		// Як у твоєму JS:
-		// u = Number(inputLoadVoltage1.value);
-		u := res.EP1.U
-
-		activePowerFactor := 1.25
-		coefficientActiveApacityWorkshop := 0.7
-
-		// calculatedMultip = n * p
-		calculatedMultip1 := multiplicationNandP(res.EP1.N, res.EP1.Pn)
-		calculatedMultip2 := multiplicationNandP(res.EP2.N, res.EP2.Pn)
-		calculatedMultip3 := multiplicationNandP(res.EP3.N, res.EP3.Pn)
-
-		// calculatedMultipInSquare = n * p^2
-		calculatedMultipInSquare1 := sumMultiplicationNandPInSquare(res.EP1.N, res.EP1.Pn)
-		calculatedMultipInSquare2 := sumMultiplicationNandPInSquare(res.EP2.N, res.EP2.Pn)
-		calculatedMultipInSquare3 := sumMultiplicationNandPInSquare(res.EP3.N, res.EP3.Pn)
-
-		// calculatedMultipK = (n*p) * k
-		calculatedMultipK1 := multiplicationK(calculatedMultip1, res.EP1.Kv)
-		calculatedMultipK2 := multiplicationK(calculatedMultip2, res.EP2.Kv)
-		calculatedMultipK3 := multiplicationK(calculatedMultip3, res.EP3.Kv)
-
-		// multiplicationTg: tg * calculatedMultipK, rounded to 1 decimal like toFixed(1), then parsed back to Number
-		calculatedMultipTg1 := multiplicationTgRounded1(res.EP1.Tg, calculatedMultipK1)
-		calculatedMultipTg2 := multiplicationTgRounded1(res.EP2.Tg, calculatedMultipK2)
-		calculatedMultipTg3 := multiplicationTgRounded1(res.EP3.Tg, calculatedMultipK3)
-
-		calculatedGroupUtilizationFactor := getGroupUtilizationFactor(
-			calculatedMultip1, calculatedMultip2, calculatedMultip3,
-			calculatedMultipK1, calculatedMultipK2, calculatedMultipK3,
-		)
-
-		calculatedEffectiveAmount := getEffectiveAmount(
-			calculatedMultip1, calculatedMultip2, calculatedMultip3,
-			calculatedMultipInSquare1, calculatedMultipInSquare2, calculatedMultipInSquare3,
-		)
-
-		calculatedActiveLoad := getcalculatedActiveLoad(activePowerFactor, calculatedMultipK1, calculatedMultipK2, calculatedMultipK3)
-		calculatedReactiveLoad := getcalculatedReactiveLoad(activePowerFactor, calculatedMultipTg1, calculatedMultipTg2, calculatedMultipTg3)
-		calculatedFullPower := getFullPower(calculatedActiveLoad, calculatedReactiveLoad)
-		calculatedGroupCurrent := getGroupCurrent(calculatedActiveLoad, u)
-
-		calculatedUtilizationRatesWorkshop := getUtilizationRatesWorkshop(
-			calculatedMultip1, calculatedMultip2, calculatedMultip3,
-			calculatedMultipK1, calculatedMultipK2, calculatedMultipK3,
-		)
-
-		calculatedEffectiveNumberEPWorkshop := getEffectiveNumberEPWorkshop(
-			calculatedMultip1, calculatedMultip2, calculatedMultip3,
-			calculatedMultipInSquare1, calculatedMultipInSquare2, calculatedMultipInSquare3,
-		)
-
-		calculatedActiveTireLoad := getActiveTireLoad(coefficientActiveApacityWorkshop)
-		calculatedReactiveLoadTires := getReactiveLoadTires(coefficientActiveApacityWorkshop)
-		calculatedFullPowerTires := getFullPowerTires(calculatedActiveTireLoad, calculatedReactiveLoadTires)
-		calculatedGroupCurrentTires := getGroupCurrentTires(calculatedActiveTireLoad, u)
-
-		// Форматування як у твоєму updateResults()
-		res.GroupUtilizationFactor = fmt.Sprintf("%.4f", calculatedGroupUtilizationFactor)
-		res.EffectiveAmount = fmt.Sprintf("%.0f", math.Ceil(calculatedEffectiveAmount))
-		res.ActivePowerFactor = fmt.Sprintf("%.2f", activePowerFactor) // у JS просто 1.25, але так читабельніше
-		res.CalculatedActiveLoad = fmt.Sprintf("%.2f", calculatedActiveLoad)
-		res.CalculatedReactiveLoad = fmt.Sprintf("%.2f", calculatedReactiveLoad)
-		res.FullPower = fmt.Sprintf("%.3f", calculatedFullPower)
-		res.GroupCurrent = fmt.Sprintf("%.2f", calculatedGroupCurrent)
-
-		res.UtilizationRatesWorkshop = fmt.Sprintf("%.2f", calculatedUtilizationRatesWorkshop)
-		res.EffectiveNumberEPWorkshop = fmt.Sprintf("%.2f", calculatedEffectiveNumberEPWorkshop)
-		res.CoefficientActiveApacityWork = fmt.Sprintf("%.1f", coefficientActiveApacityWorkshop)
-
-		res.ActiveTireLoad = fmt.Sprintf("%.1f", calculatedActiveTireLoad)
-		res.ReactiveTireLoad = fmt.Sprintf("%.1f", calculatedReactiveLoadTires)
-		res.FullPowerTires = fmt.Sprintf("%.1f", calculatedFullPowerTires)
-		res.GroupCurrentTires = fmt.Sprintf("%.2f", calculatedGroupCurrentTires)
-
-		_ = tpl.Execute(w, res)
+		res := readResults(r)
+		calculate(&res)
+		render(w, r, res)
 		return
 
 	default:
@@ -160,8 +155,140 @@ func handleIndex(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// render дописує список збережених проєктів користувача і віддає сторінку.
+func render(w http.ResponseWriter, r *http.Request, res Results) {
+	projects, err := store.List(userID(w, r))
+	if err != nil {
+		log.Println("projects list error:", err)
+	}
+	res.Projects = projects
+	if err := tpl.Execute(w, res); err != nil {
+		log.Println("template error:", err)
+	}
+}
+
+// calculate рахує всі результати за введеними рядками та коефіцієнтами.
+func calculate(res *Results) {
+	res.Submitted = true
+
+	// Як у твоєму JS:
+	// u = Number(inputLoadVoltage1.value);
+	u := res.EP1.U
+
+	activePowerFactor := res.Params.ActivePowerFactor
+	coefficientActiveApacityWorkshop := res.Params.CoefficientActiveApacityWorkshop
+
+	// calculatedMultip = n * p
+	calculatedMultip1 := multiplicationNandP(res.EP1.N, res.EP1.Pn)
+	calculatedMultip2 := multiplicationNandP(res.EP2.N, res.EP2.Pn)
+	calculatedMultip3 := multiplicationNandP(res.EP3.N, res.EP3.Pn)
+
+	// calculatedMultipInSquare = n * p^2
+	calculatedMultipInSquare1 := sumMultiplicationNandPInSquare(res.EP1.N, res.EP1.Pn)
+	calculatedMultipInSquare2 := sumMultiplicationNandPInSquare(res.EP2.N, res.EP2.Pn)
+	calculatedMultipInSquare3 := sumMultiplicationNandPInSquare(res.EP3.N, res.EP3.Pn)
+
+	// calculatedMultipK = (n*p) * k
+	calculatedMultipK1 := multiplicationK(calculatedMultip1, res.EP1.Kv)
+	calculatedMultipK2 := multiplicationK(calculatedMultip2, res.EP2.Kv)
+	calculatedMultipK3 := multiplicationK(calculatedMultip3, res.EP3.Kv)
+
+	// multiplicationTg: tg * calculatedMultipK; у legacy-режимі rounded to 1 decimal like toFixed(1)
+	calculatedMultipTg1 := res.Params.reactive(res.EP1.Tg, calculatedMultipK1)
+	calculatedMultipTg2 := res.Params.reactive(res.EP2.Tg, calculatedMultipK2)
+	calculatedMultipTg3 := res.Params.reactive(res.EP3.Tg, calculatedMultipK3)
+
+	calculatedGroupUtilizationFactor := getGroupUtilizationFactor(
+		calculatedMultip1, calculatedMultip2, calculatedMultip3,
+		calculatedMultipK1, calculatedMultipK2, calculatedMultipK3,
+	)
+
+	calculatedEffectiveAmount := getEffectiveAmount(
+		calculatedMultip1, calculatedMultip2, calculatedMultip3,
+		calculatedMultipInSquare1, calculatedMultipInSquare2, calculatedMultipInSquare3,
+	)
+
+	calculatedActiveLoad := getcalculatedActiveLoad(activePowerFactor, calculatedMultipK1, calculatedMultipK2, calculatedMultipK3)
+	calculatedReactiveLoad := getcalculatedReactiveLoad(activePowerFactor, calculatedMultipTg1, calculatedMultipTg2, calculatedMultipTg3)
+	calculatedFullPower := getFullPower(calculatedActiveLoad, calculatedReactiveLoad)
+	calculatedGroupCurrent := getGroupCurrent(calculatedActiveLoad, u)
+
+	calculatedUtilizationRatesWorkshop := getUtilizationRatesWorkshop(
+		calculatedMultip1, calculatedMultip2, calculatedMultip3,
+		calculatedMultipK1, calculatedMultipK2, calculatedMultipK3,
+	)
+
+	calculatedEffectiveNumberEPWorkshop := getEffectiveNumberEPWorkshop(
+		calculatedMultip1, calculatedMultip2, calculatedMultip3,
+		calculatedMultipInSquare1, calculatedMultipInSquare2, calculatedMultipInSquare3,
+	)
+
+	calculatedActiveTireLoad := getActiveTireLoad(coefficientActiveApacityWorkshop)
+	calculatedReactiveLoadTires := getReactiveLoadTires(coefficientActiveApacityWorkshop)
+	calculatedFullPowerTires := getFullPowerTires(calculatedActiveTireLoad, calculatedReactiveLoadTires)
+	calculatedGroupCurrentTires := getGroupCurrentTires(calculatedActiveTireLoad, u)
+
+	res.Table = buildLoadTable(res,
+		loadTotals{
+			Kv: calculatedGroupUtilizationFactor, Ne: res.Params.effectiveAmount(calculatedEffectiveAmount), Kp: activePowerFactor,
+			Pp: calculatedActiveLoad, Qp: calculatedReactiveLoad, Sp: calculatedFullPower, Ip: calculatedGroupCurrent,
+		},
+		loadTotals{
+			Kv: calculatedUtilizationRatesWorkshop, Ne: calculatedEffectiveNumberEPWorkshop, Kp: coefficientActiveApacityWorkshop,
+			Pp: calculatedActiveTireLoad, Qp: calculatedReactiveLoadTires, Sp: calculatedFullPowerTires, Ip: calculatedGroupCurrentTires,
+		},
+	)
+
+	// Форматування як у твоєму updateResults()
+	res.GroupUtilizationFactor = fmt.Sprintf("%.4f", calculatedGroupUtilizationFactor)
+	if res.Params.Precision == PrecisionExact {
+		res.EffectiveAmount = fmt.Sprintf("%.2f", calculatedEffectiveAmount)
+	} else {
+		res.EffectiveAmount = fmt.Sprintf("%.0f", math.Ceil(calculatedEffectiveAmount))
+	}
+	res.ActivePowerFactor = fmt.Sprintf("%.2f", activePowerFactor) // у JS просто 1.25, але так читабельніше
+	res.CalculatedActiveLoad = fmt.Sprintf("%.2f", calculatedActiveLoad)
+	res.CalculatedReactiveLoad = fmt.Sprintf("%.2f", calculatedReactiveLoad)
+	res.FullPower = fmt.Sprintf("%.3f", calculatedFullPower)
+	res.GroupCurrent = fmt.Sprintf("%.2f", calculatedGroupCurrent)
+
+	res.UtilizationRatesWorkshop = fmt.Sprintf("%.2f", calculatedUtilizationRatesWorkshop)
+	res.EffectiveNumberEPWorkshop = fmt.Sprintf("%.2f", calculatedEffectiveNumberEPWorkshop)
+	res.CoefficientActiveApacityWork = fmt.Sprintf("%.1f", coefficientActiveApacityWorkshop)
+
+	res.ActiveTireLoad = fmt.Sprintf("%.1f", calculatedActiveTireLoad)
+	res.ReactiveTireLoad = fmt.Sprintf("%.1f", calculatedReactiveLoadTires)
+	res.FullPowerTires = fmt.Sprintf("%.1f", calculatedFullPowerTires)
+	res.GroupCurrentTires = fmt.Sprintf("%.2f", calculatedGroupCurrentTires)
+}
+
 // ----- читання інпутів -----
 
+func readResults(r *http.Request) Results {
+	res := Results{
+		EP1:         readEPRow(r, ""),
+		EP2:         readEPRow(r, "-2"),
+		EP3:         readEPRow(r, "-3"),
+		Params:      readParams(r),
+		ProjectID:   r.FormValue("project-id"),
+		ProjectName: strings.TrimSpace(r.FormValue("project-name")),
+	}
+	return res
+}
+
+// readParams — порожнє поле означає значення за замовчуванням.
+func readParams(r *http.Request) Params {
+	p := defaultParams()
+	if v := parseFloat(r.FormValue("active-power-factor")); v > 0 {
+		p.ActivePowerFactor = v
+	}
+	if v := parseFloat(r.FormValue("coefficient-active-capacity-workshop")); v > 0 {
+		p.CoefficientActiveApacityWorkshop = v
+	}
+	p.Precision = parsePrecision(r.FormValue("precision"))
+	return p
+}
+
 func readEPRow(r *http.Request, suffix string) EPRow {
 	row := EPRow{
 		Name: r.FormValue("name-of-EP" + suffix),
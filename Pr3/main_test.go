@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// exampleResults — приклад з placeholder-ів форми.
+func exampleResults(mode PrecisionMode) Results {
+	p := defaultParams()
+	p.Precision = mode
+	return Results{
+		EP1:    EPRow{Name: "Шліфувальний верстат", Eta: 0.92, Cos: 0.9, U: 0.38, N: 4, Pn: 28, Kv: 0.15, Tg: 1.33},
+		EP2:    EPRow{Name: "Полірувальний верстат", Eta: 0.92, Cos: 0.9, U: 0.38, N: 1, Pn: 40, Kv: 0.29, Tg: 1},
+		EP3:    EPRow{Name: "Циркулярна пила", Eta: 0.92, Cos: 0.9, U: 0.38, N: 1, Pn: 36, Kv: 0.3, Tg: 1.64},
+		Params: p,
+	}
+}
+
+// Поля, в яких legacy- і exact-режими розходяться на прикладі.
+func TestPrecisionModesDiffer(t *testing.T) {
+	tests := []struct {
+		mode     PrecisionMode
+		ne       string    // nе ШР: legacy округлює вгору до цілого
+		q        string    // Qр ШР, квар
+		s        string    // Sр ШР, кВ·А
+		reactive []float64 // n·Pн·Kв·tgφ рядків ЕП: legacy — toFixed(1)
+	}{
+		{PrecisionLegacy, "15", "147.82", "196.493", []float64{22.3, 11.6, 17.7}},
+		{PrecisionExact, "14.59", "147.89", "196.546", []float64{22.344, 11.6, 17.712}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			res := exampleResults(tt.mode)
+			calculate(&res)
+			if res.EffectiveAmount != tt.ne {
+				t.Errorf("nе = %s, want %s", res.EffectiveAmount, tt.ne)
+			}
+			if res.CalculatedReactiveLoad != tt.q {
+				t.Errorf("Qр = %s, want %s", res.CalculatedReactiveLoad, tt.q)
+			}
+			if res.FullPower != tt.s {
+				t.Errorf("Sр = %s, want %s", res.FullPower, tt.s)
+			}
+			for i, want := range tt.reactive {
+				if got := res.Table.Rows[i].NPnKvTg; math.Abs(got-want) > 1e-9 {
+					t.Errorf("рядок %d: n·Pн·Kв·tgφ = %g, want %g", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Решта результатів від режиму точності не залежить.
+func TestPrecisionModesAgree(t *testing.T) {
+	legacy, exact := exampleResults(PrecisionLegacy), exampleResults(PrecisionExact)
+	calculate(&legacy)
+	calculate(&exact)
+
+	legacy.EffectiveAmount, exact.EffectiveAmount = "", ""
+	legacy.CalculatedReactiveLoad, exact.CalculatedReactiveLoad = "", ""
+	legacy.FullPower, exact.FullPower = "", ""
+	if legacy.Output != exact.Output {
+		t.Errorf("legacy %+v\nexact  %+v", legacy.Output, exact.Output)
+	}
+	if legacy.CalculatedActiveLoad != "129.45" || legacy.GroupCurrent != "340.66" {
+		t.Errorf("Pр = %s, Iр = %s, want 129.45, 340.66", legacy.CalculatedActiveLoad, legacy.GroupCurrent)
+	}
+	if legacy.FullPowerTires != "699.0" || legacy.GroupCurrentTires != "1385.26" {
+		t.Errorf("Sр шин = %s, Iр шин = %s, want 699.0, 1385.26", legacy.FullPowerTires, legacy.GroupCurrentTires)
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Версія формату JSON-файлу проєкту. Збільшувати при несумісних змінах.
+const projectVersion = 1
+
+// Project — повний знімок форми: усі рядки ЕП, коефіцієнти та результати.
+type Project struct {
+	Version int       `json:"version"`
+	ID      string    `json:"id,omitempty"`
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"savedAt"`
+	Rows    []EPRow   `json:"rows"`
+	Params  Params    `json:"params"`
+	Results *Output   `json:"results,omitempty"`
+}
+
+// ProjectInfo — рядок у списку збережених проєктів.
+type ProjectInfo struct {
+	ID      string
+	Name    string
+	SavedAt time.Time
+}
+
+func projectFromResults(res Results) Project {
+	p := Project{
+		Version: projectVersion,
+		Name:    res.ProjectName,
+		SavedAt: time.Now().UTC(),
+		Rows:    []EPRow{res.EP1, res.EP2, res.EP3},
+		Params:  res.Params,
+	}
+	if res.Submitted {
+		out := res.Output
+		p.Results = &out
+	}
+	return p
+}
+
+// toResults повертає проєкт у форму; результати завжди перераховуються,
+// щоб не показувати значення, які не відповідають введеним даним.
+func (p Project) toResults() Results {
+	res := Results{Params: p.Params, ProjectID: p.ID, ProjectName: p.Name}
+	rows := make([]EPRow, 3)
+	copy(rows, p.Rows)
+	res.EP1, res.EP2, res.EP3 = rows[0], rows[1], rows[2]
+	calculate(&res)
+	return res
+}
+
+func decodeProject(data []byte) (Project, error) {
+	var p Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("некоректний JSON: %w", err)
+	}
+	if p.Version < 1 || p.Version > projectVersion {
+		return p, fmt.Errorf("непідтримувана версія файлу проєкту: %d", p.Version)
+	}
+	if len(p.Rows) > 3 {
+		return p, fmt.Errorf("форма підтримує не більше 3 рядків ЕП, у файлі %d", len(p.Rows))
+	}
+	def := defaultParams()
+	if p.Params.ActivePowerFactor <= 0 {
+		p.Params.ActivePowerFactor = def.ActivePowerFactor
+	}
+	if p.Params.CoefficientActiveApacityWorkshop <= 0 {
+		p.Params.CoefficientActiveApacityWorkshop = def.CoefficientActiveApacityWorkshop
+	}
+	// файли без поля precision створені до появи режимів — це legacy
+	p.Params.Precision = parsePrecision(string(p.Params.Precision))
+	return p, nil
+}
+
+// ----- збереження на сервері -----
+
+// ProjectStore зберігає проєкти у файлах projects/<користувач>/<id>.json.
+type ProjectStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+var store = &ProjectStore{dir: "projects"}
+
+var safeID = regexp.MustCompile(`^[0-9a-f]{16,32}$`)
+
+var errProjectNotFound = errors.New("проєкт не знайдено")
+
+func (s *ProjectStore) userDir(user string) (string, error) {
+	if !safeID.MatchString(user) {
+		return "", errors.New("некоректний ідентифікатор користувача")
+	}
+	return filepath.Join(s.dir, user), nil
+}
+
+func (s *ProjectStore) path(user, id string) (string, error) {
+	dir, err := s.userDir(user)
+	if err != nil {
+		return "", err
+	}
+	if !safeID.MatchString(id) {
+		return "", errProjectNotFound
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+func (s *ProjectStore) Save(user string, p Project) (Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.ID == "" {
+		p.ID = newID()
+	}
+	path, err := s.path(user, p.ID)
+	if err != nil {
+		return p, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return p, err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return p, err
+	}
+	return p, os.WriteFile(path, data, 0o644)
+}
+
+func (s *ProjectStore) Load(user, id string) (Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(user, id)
+	if err != nil {
+		return Project{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Project{}, errProjectNotFound
+	}
+	if err != nil {
+		return Project{}, err
+	}
+	return decodeProject(data)
+}
+
+func (s *ProjectStore) Delete(user, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(user, id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return errProjectNotFound
+	}
+	return err
+}
+
+// List повертає проєкти користувача, новіші — першими.
+func (s *ProjectStore) List(user string) ([]ProjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir, err := s.userDir(user)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []ProjectInfo
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		p, err := decodeProject(data)
+		if err != nil {
+			log.Printf("skip project %s: %v", e.Name(), err)
+			continue
+		}
+		list = append(list, ProjectInfo{ID: p.ID, Name: p.Name, SavedAt: p.SavedAt})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SavedAt.After(list[j].SavedAt) })
+	return list, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ----- користувач -----
+
+const userCookie = "pr3-user"
+
+// userID — авторизації немає, тому користувача розрізняємо за cookie.
+func userID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(userCookie); err == nil && safeID.MatchString(c.Value) {
+		return c.Value
+	}
+	id := newID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     userCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	// щоб наступні виклики в межах цього ж запиту бачили той самий id
+	r.AddCookie(&http.Cookie{Name: userCookie, Value: id})
+	return id
+}
+
+// ----- обробники -----
+
+func handleProjectExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res := readResults(r)
+	calculate(&res)
+
+	name := res.ProjectName
+	if name == "" {
+		name = "project"
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName(name)+".json"))
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(projectFromResults(res)); err != nil {
+		log.Println("export error:", err)
+	}
+}
+
+func handleProjectImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	file, _, err := r.FormFile("project-file")
+	if err != nil {
+		render(w, r, Results{Params: defaultParams(), Error: "Оберіть JSON-файл проєкту"})
+		return
+	}
+	defer file.Close()
+
+	var p Project
+	data, err := io.ReadAll(io.LimitReader(file, 1<<20))
+	if err == nil {
+		p, err = decodeProject(data)
+	}
+	if err != nil {
+		render(w, r, Results{Params: defaultParams(), Error: "Не вдалося імпортувати проєкт: " + err.Error()})
+		return
+	}
+
+	res := p.toResults()
+	res.ProjectID = "" // імпортований файл зберігається як новий проєкт
+	res.Message = "Проєкт імпортовано"
+	render(w, r, res)
+}
+
+func handleProjectSave(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res := readResults(r)
+	calculate(&res)
+	if res.ProjectName == "" {
+		res.Error = "Вкажіть назву проєкту"
+		render(w, r, res)
+		return
+	}
+
+	p := projectFromResults(res)
+	p.ID = res.ProjectID
+	if p, err := store.Save(userID(w, r), p); err != nil {
+		log.Println("save error:", err)
+		res.Error = "Не вдалося зберегти проєкт"
+	} else {
+		res.ProjectID = p.ID
+		res.Message = "Проєкт «" + res.ProjectName + "» збережено"
+	}
+	render(w, r, res)
+}
+
+func handleProjectLoad(w http.ResponseWriter, r *http.Request) {
+	p, err := store.Load(userID(w, r), r.FormValue("id"))
+	if err != nil {
+		render(w, r, Results{Params: defaultParams(), Error: err.Error()})
+		return
+	}
+	res := p.toResults()
+	res.Message = "Проєкт «" + p.Name + "» завантажено"
+	render(w, r, res)
+}
+
+func handleProjectDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := store.Delete(userID(w, r), r.FormValue("id")); err != nil {
+		render(w, r, Results{Params: defaultParams(), Error: err.Error()})
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// fileName прибирає з назви символи, небажані в імені файлу.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+}
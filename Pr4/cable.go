@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Каталог кабелів 10 кВ: стандартні перерізи з тривало допустимими
+// струмами при прокладанні в землі та коефіцієнт термічної стійкості C_T.
+type CableSection struct {
+	S    float64 `json:"s"`    // мм²
+	Idop float64 `json:"idop"` // А
+}
+
+type CableType struct {
+	ID          string         `json:"id"`
+	Description string         `json:"description"`
+	Material    string         `json:"material"`   // al | cu
+	Insulation  string         `json:"insulation"` // paper | plastic
+	Unom        float64        `json:"unom"`       // кВ
+	CT          float64        `json:"ct"`         // А·с^½/мм²
+	Sections    []CableSection `json:"sections"`
+}
+
+var alPaper10kV = []CableSection{
+	{16, 75}, {25, 90}, {35, 115}, {50, 140}, {70, 165},
+	{95, 205}, {120, 240}, {150, 275}, {185, 310}, {240, 355},
+}
+
+var cableCatalog = []CableType{
+	{ID: "ААБ", Description: "алюмінієві жили, паперова ізоляція, алюмінієва оболонка, броня", Material: "al", Insulation: "paper", Unom: 10, CT: 92, Sections: alPaper10kV},
+	{ID: "ААШв", Description: "алюмінієві жили, паперова ізоляція, алюмінієва оболонка, шланг ПВХ", Material: "al", Insulation: "paper", Unom: 10, CT: 92, Sections: alPaper10kV},
+	{ID: "АСБ", Description: "алюмінієві жили, паперова ізоляція, свинцева оболонка, броня", Material: "al", Insulation: "paper", Unom: 10, CT: 92, Sections: alPaper10kV},
+	{ID: "СБ", Description: "мідні жили, паперова ізоляція, свинцева оболонка, броня", Material: "cu", Insulation: "paper", Unom: 10, CT: 141, Sections: []CableSection{
+		{16, 95}, {25, 120}, {35, 150}, {50, 180}, {70, 215},
+		{95, 265}, {120, 310}, {150, 355}, {185, 400}, {240, 460},
+	}},
+	{ID: "АПвЕгП", Description: "алюмінієві жили, ізоляція зі зшитого поліетилену", Material: "al", Insulation: "plastic", Unom: 10, CT: 94, Sections: []CableSection{
+		{35, 135}, {50, 160}, {70, 195}, {95, 235}, {120, 265},
+		{150, 300}, {185, 340}, {240, 390},
+	}},
+}
+
+// Допустиме перевантаження кабелів до 10 кВ у післяаварійному режимі.
+const postAccidentOverload = 1.3
+
+func findCable(id string) (CableType, bool) {
+	for _, c := range cableCatalog {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return CableType{}, false
+}
+
+// CableCriterion — мінімальний стандартний переріз за одним критерієм.
+type CableCriterion struct {
+	Name      string  `json:"name"`
+	Required  string  `json:"required"`  // умова, яку перевіряли
+	Section   float64 `json:"section"`   // мм², 0 — жоден переріз каталогу не підходить
+	Governing bool    `json:"governing"` // саме цей критерій визначив вибір
+}
+
+type CableSelection struct {
+	Cable    CableType        `json:"cable"`
+	Section  CableSection     `json:"section"`
+	Criteria []CableCriterion `json:"criteria"`
+}
+
+// GoverningNames — назви визначальних критеріїв для виводу.
+func (s CableSelection) GoverningNames() string {
+	names := ""
+	for _, c := range s.Criteria {
+		if c.Governing {
+			if names != "" {
+				names += ", "
+			}
+			names += c.Name
+		}
+	}
+	return names
+}
+
+// thermalMinSection — мінімальний переріз за термічною стійкістю
+// s = Iк·√tф / C_T (Iк в кА).
+func thermalMinSection(current, time, ct float64) float64 {
+	return current * 1000 * math.Sqrt(time) / ct
+}
+
+// selectCable підбирає найменший стандартний переріз, що задовольняє
+// економічну густину струму, тривало допустимий струм у нормальному та
+// післяаварійному режимах і термічну стійкість.
+func selectCable(cable CableType, iNormal, iPostAccident, sEconomic, sThermal float64) (CableSelection, error) {
+	first := func(ok func(CableSection) bool) float64 {
+		for _, s := range cable.Sections {
+			if ok(s) {
+				return s.S
+			}
+		}
+		return 0
+	}
+
+	sel := CableSelection{Cable: cable}
+	sel.Criteria = []CableCriterion{
+		{
+			Name:     "економічна густина струму",
+			Required: fmt.Sprintf("s ≥ %.1f мм²", sEconomic),
+			Section:  first(func(s CableSection) bool { return s.S >= sEconomic }),
+		},
+		{
+			Name:     "тривало допустимий струм",
+			Required: fmt.Sprintf("Iдоп ≥ %.1f А", iNormal),
+			Section:  first(func(s CableSection) bool { return s.Idop >= iNormal }),
+		},
+		{
+			Name:     "післяаварійний режим",
+			Required: fmt.Sprintf("%.1f·Iдоп ≥ %.1f А", postAccidentOverload, iPostAccident),
+			Section:  first(func(s CableSection) bool { return postAccidentOverload*s.Idop >= iPostAccident }),
+		},
+		{
+			Name:     "термічна стійкість",
+			Required: fmt.Sprintf("s ≥ %.1f мм²", sThermal),
+			Section:  first(func(s CableSection) bool { return s.S >= sThermal }),
+		},
+	}
+
+	var chosen float64
+	for _, c := range sel.Criteria {
+		if c.Section == 0 {
+			return sel, fmt.Errorf("жоден переріз кабелю %s не задовольняє критерій «%s» (%s) — потрібні паралельні кабелі або інший тип", cable.ID, c.Name, c.Required)
+		}
+		chosen = math.Max(chosen, c.Section)
+	}
+	for i, c := range sel.Criteria {
+		sel.Criteria[i].Governing = c.Section == chosen
+	}
+	for _, s := range cable.Sections {
+		if s.S == chosen {
+			sel.Section = s
+		}
+	}
+	return sel, nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+)
+
+// Однолінійна схема мережі у форматі SVG. Вузли розміщуються ярусами за
+// відстанню від джерела: шини — горизонтальні лінії, система — коло з «~»,
+// трансформатор — два кола, лінія — відрізок. Елементи підписуються опором
+// прямої послідовності нормального режиму, точки КЗ — струмами з report.
+
+const (
+	diagramColumn = 560 // ширина стовпця вузла разом із підписами
+	diagramLevel  = 190 // висота ярусу
+	diagramBar    = 140 // довжина шини
+	diagramTop    = 90  // відступ для джерел
+	diagramLeft   = 160 // місце для назв шин ліворуч
+)
+
+type busPos struct{ x, y float64 }
+
+// layout розставляє вузли: ярус — кількість елементів від шин із джерелом.
+func (n Network) layout() ([]busPos, float64, float64) {
+	idx := n.busIndex()
+	depth := make([]int, len(n.Buses))
+	for i := range depth {
+		depth[i] = -1
+	}
+	var queue []int
+	for _, e := range n.Elements {
+		if e.Type == "source" && depth[idx[e.To]] < 0 {
+			depth[idx[e.To]] = 0
+			queue = append(queue, idx[e.To])
+		}
+	}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		for _, e := range n.Elements {
+			if e.Type == "source" {
+				continue
+			}
+			from, to := idx[e.From], idx[e.To]
+			for _, pair := range [][2]int{{from, to}, {to, from}} {
+				if pair[0] == b && depth[pair[1]] < 0 {
+					depth[pair[1]] = depth[b] + 1
+					queue = append(queue, pair[1])
+				}
+			}
+		}
+	}
+
+	// не з'єднані з джерелом вузли — на окремий нижній ярус
+	maxDepth := 0
+	for _, d := range depth {
+		maxDepth = max(maxDepth, d)
+	}
+	for i, d := range depth {
+		if d < 0 {
+			depth[i] = maxDepth + 1
+		}
+	}
+
+	pos := make([]busPos, len(n.Buses))
+	columns := map[int]int{}
+	width, height := 0.0, 0.0
+	for i, d := range depth {
+		col := columns[d]
+		columns[d]++
+		pos[i] = busPos{
+			x: diagramLeft + diagramBar/2 + float64(col)*diagramColumn,
+			y: diagramTop + 40 + float64(d)*diagramLevel,
+		}
+		width = max(width, pos[i].x+diagramBar/2+340)
+		height = max(height, pos[i].y+110)
+	}
+	return pos, width, height
+}
+
+func svgText(b *strings.Builder, x, y float64, anchor, class, text string) {
+	if class != "" {
+		class = ` class="` + class + `"`
+	}
+	fmt.Fprintf(b, `<text x="%.0f" y="%.0f" text-anchor="%s"%s>%s</text>`+"\n", x, y, anchor, class, html.EscapeString(text))
+}
+
+func formatZ(z complex128) string {
+	return fmt.Sprintf("Z = %.2f + j%.2f Ом", real(z), imag(z))
+}
+
+// Diagram повертає SVG схеми; report може бути nil, тоді точки КЗ
+// позначаються без струмів.
+func (n Network) Diagram(report *NetworkReport) string {
+	idx := n.busIndex()
+	pos, width, height := n.layout()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+	b.WriteString(`<style>line,circle,path{stroke:#222;stroke-width:1.5;fill:none}.bus{stroke-width:5}.fault{stroke:#b00020}text.fault{fill:#b00020;stroke:none;font-weight:bold}.name{font-weight:bold}</style>` + "\n")
+	fmt.Fprintf(&b, `<rect width="%.0f" height="%.0f" fill="#fff"/>`+"\n", width, height)
+
+	for i, bus := range n.Buses {
+		p := pos[i]
+		fmt.Fprintf(&b, `<line class="bus" x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f"/>`+"\n", p.x-diagramBar/2, p.y, p.x+diagramBar/2, p.y)
+		label := bus.ID
+		if bus.Name != "" {
+			label = bus.Name
+		}
+		svgText(&b, p.x-diagramBar/2-6, p.y-4, "end", "name", label)
+		svgText(&b, p.x-diagramBar/2-6, p.y+12, "end", "", fmt.Sprintf("%g кВ", bus.Kv))
+	}
+
+	// кілька елементів між тими самими вузлами розносяться по горизонталі
+	offsets := map[[2]int]int{}
+	for _, e := range n.Elements {
+		if e.Type == "source" {
+			p := pos[idx[e.To]]
+			off := float64(offsets[[2]int{-1, idx[e.To]}]) * 60
+			offsets[[2]int{-1, idx[e.To]}]++
+			x, y := p.x-30+off, p.y-diagramTop+20
+			fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="16"/>`+"\n", x, y)
+			fmt.Fprintf(&b, `<path d="M%.0f %.0f q5 -8 10 0 t10 0"/>`+"\n", x-10, y)
+			fmt.Fprintf(&b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f"/>`+"\n", x, y+16, x, p.y)
+			svgText(&b, x+22, y-4, "start", "name", e.Name)
+			svgText(&b, x+22, y+11, "start", "", formatZ(elementZ(e, n.Buses[idx[e.To]].Kv, seqPositive, false)))
+			continue
+		}
+
+		from, to := idx[e.From], idx[e.To]
+		key := [2]int{min(from, to), max(from, to)}
+		off := float64(offsets[key]) * 70
+		offsets[key]++
+		a, c := pos[from], pos[to]
+		if a.y > c.y {
+			a, c = c, a
+		}
+		x1, x2 := a.x-30+off, c.x-30+off
+		midY := (a.y + c.y) / 2
+		if a.y == c.y {
+			// вузли одного ярусу з'єднуються дугою під шинами
+			fmt.Fprintf(&b, `<path d="M%.0f %.0f V%.0f H%.0f V%.0f"/>`+"\n", x1, a.y, a.y+60, x2, c.y)
+			midY = a.y + 60
+		} else {
+			fmt.Fprintf(&b, `<path d="M%.0f %.0f V%.0f H%.0f V%.0f"/>`+"\n", x1, a.y, midY, x2, c.y)
+		}
+		mx := (x1 + x2) / 2
+
+		switch e.Type {
+		case "transformer":
+			fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="13" style="fill:#fff"/>`+"\n", mx, midY-9)
+			fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="13" style="fill:#fff"/>`+"\n", mx, midY+9)
+			fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="13"/>`+"\n", mx, midY-9)
+		case "line":
+			fmt.Fprintf(&b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f"/>`+"\n", mx-8, midY+6, mx+8, midY-6)
+		}
+		detail := ""
+		switch e.Type {
+		case "transformer":
+			detail = fmt.Sprintf("%g МВ·А, uк = %g %%", e.Snom, e.Uk)
+		case "line":
+			detail = fmt.Sprintf("%g км", e.Length)
+		}
+		if e.OffInMin {
+			detail += ", вимк. у мін. режимі"
+		}
+		svgText(&b, mx+22, midY-8, "start", "name", e.Name+" "+detail)
+		svgText(&b, mx+22, midY+8, "start", "", fmt.Sprintf("%s (%g кВ)", formatZ(elementZ(e, n.Buses[from].Kv, seqPositive, false)), n.Buses[from].Kv))
+	}
+
+	for i, f := range n.Faults {
+		p := pos[idx[f.Bus]]
+		x := p.x + diagramBar/2 - 12
+		fmt.Fprintf(&b, `<path class="fault" d="M%.0f %.0f l-6 14 h10 l-6 16"/>`+"\n", x, p.y+3)
+		svgText(&b, x+10, p.y+22, "start", "fault", f.Name)
+		if report == nil || i >= len(report.Faults) {
+			continue
+		}
+		r := report.Faults[i]
+		x += 40
+		svgText(&b, x, p.y+22, "start", "", fmt.Sprintf("Z = %.2f Ом, I(3) = %.0f А, I(2) = %.0f А", r.Normal.Z, r.Normal.I3, r.Normal.I2))
+		svgText(&b, x, p.y+37, "start", "", fmt.Sprintf("min: Z = %.2f Ом, I(3) = %.0f А, I(2) = %.0f А", r.Min.Z, r.Min.I3, r.Min.I2))
+		svgText(&b, x, p.y+52, "start", "", fmt.Sprintf("iуд = %.2f кА", r.Normal.Ish))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// networkSVGHandler віддає схему з форми мережі файлом для завантаження.
+func networkSVGHandler(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	n, err := parseNetwork([]byte(r.FormValue("network")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var report *NetworkReport
+	if rep, err := n.Solve(); err == nil {
+		report = &rep
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="network.svg"`)
+	_, _ = w.Write([]byte(n.Diagram(report)))
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Розрахунки task1–task3. Усі функції повертають float64 без округлення;
+// форматування виконується лише в шаблоні (див. templateFuncs).
+
+var sqrt3 = math.Sqrt(3)
+
+// ----- task1: вибір кабелю -----
+
+type Task1Input struct {
+	Current        float64 // Iк, кА
+	HighVoltage    float64 // Uном, кВ
+	Time           float64 // tф, с
+	CalculatedLoad float64 // Sм, кВ·А
+	Hours          float64 // Tм, год
+	CableType      string
+	Conductor      string // ID з conductors; порожній — за типом кабелю
+}
+
+type Task1Result struct {
+	Cable     CableType
+	Conductor Conductor
+	J         float64 // А/мм²
+	JBand     string
+
+	CurrentNormal        float64 // А
+	CurrentPostAccident  float64 // А
+	EconomicCrossSection float64 // мм²
+	ThermalStability     float64 // мм²
+
+	Selection  *CableSelection
+	CableError string
+}
+
+func getCalculatedCurrentNormal(s, u float64) float64 {
+	return (s / 2) / (sqrt3 * u)
+}
+
+func getCalculatedCurrentPostAccident(i float64) float64 {
+	return 2 * i
+}
+
+func getEconomicCrossSection(calculatedCurrentNormal, j float64) float64 {
+	return calculatedCurrentNormal / j
+}
+
+func calculateTask1(in Task1Input) Task1Result {
+	cable, ok := findCable(in.CableType)
+	if !ok {
+		cable = cableCatalog[0]
+	}
+	conductor, ok := findConductor(in.Conductor)
+	if !ok {
+		conductor, _ = conductorFor(cable.Material, cable.Insulation)
+	}
+
+	res := Task1Result{Cable: cable, Conductor: conductor}
+	res.J = determineJ(conductor, in.Hours)
+	res.JBand = jekBands[jekBand(in.Hours)]
+	res.CurrentNormal = getCalculatedCurrentNormal(in.CalculatedLoad, in.HighVoltage)
+	res.CurrentPostAccident = getCalculatedCurrentPostAccident(res.CurrentNormal)
+	res.EconomicCrossSection = getEconomicCrossSection(res.CurrentNormal, res.J)
+	res.ThermalStability = thermalMinSection(in.Current, in.Time, cable.CT)
+
+	if in.HighVoltage > cable.Unom {
+		res.CableError = fmt.Sprintf("Кабель %s розрахований на напругу до %g кВ", cable.ID, cable.Unom)
+		return res
+	}
+	selection, err := selectCable(cable, res.CurrentNormal, res.CurrentPostAccident, res.EconomicCrossSection, res.ThermalStability)
+	if err != nil {
+		res.CableError = err.Error()
+		return res
+	}
+	res.Selection = &selection
+	return res
+}
+
+// ----- task2: КЗ на шинах 10 кВ ГПП -----
+
+type Task2Input struct {
+	PowerKz     float64 `json:"powerKz"`     // Sк, МВ·А
+	Transformer string  `json:"transformer"` // ID з transformerCatalog
+	Ucn         float64 `json:"ucn"`         // Uср.ном, кВ
+	Uk          float64 `json:"uk"`          // uк, %
+	Snom        float64 `json:"snom"`        // Sном.т, МВ·А
+}
+
+// withDefaults доповнює незадані параметри з каталогу трансформаторів.
+// Sк значення за замовчуванням не має: Xс = U²/Sк.
+func (in Task2Input) withDefaults() (Task2Input, error) {
+	if !(in.PowerKz > 0) {
+		return in, errors.New("потужність КЗ системи Sк має бути більшою за нуль")
+	}
+	if in.Transformer == "" {
+		in.Transformer = defaultTransformer
+	}
+	t, ok := findTransformer(in.Transformer)
+	if !ok {
+		return in, fmt.Errorf("трансформатор %q відсутній у каталозі", in.Transformer)
+	}
+	if in.Ucn <= 0 {
+		in.Ucn = 10.5
+	}
+	if in.Uk <= 0 {
+		in.Uk = t.Uk
+	}
+	if in.Snom <= 0 {
+		in.Snom = t.Snom
+	}
+	return in, nil
+}
+
+type Task2Result struct {
+	Input Task2Input `json:"input"` // з підставленими значеннями за замовчуванням
+
+	Xs                   float64 `json:"xs"`              // Ом
+	Xt                   float64 `json:"xt"`              // Ом
+	TotalResistance      float64 `json:"totalResistance"` // Ом
+	InitialCurrentValues float64 `json:"i3"`              // кА
+}
+
+func getXs(u, s float64) float64 {
+	return math.Pow(u, 2) / s
+}
+
+func getXt(ucn, uk, s float64) float64 {
+	return (uk / 100) * (math.Pow(ucn, 2) / s)
+}
+
+func getInitialCurrentValues(ucn, totalResistance float64) float64 {
+	return ucn / (sqrt3 * totalResistance)
+}
+
+func calculateTask2(in Task2Input) (Task2Result, error) {
+	in, err := in.withDefaults()
+	if err != nil {
+		return Task2Result{}, err
+	}
+
+	res := Task2Result{Input: in}
+	res.Xs = getXs(in.Ucn, in.PowerKz)
+	res.Xt = getXt(in.Ucn, in.Uk, in.Snom)
+	res.TotalResistance = res.Xs + res.Xt
+	res.InitialCurrentValues = getInitialCurrentValues(in.Ucn, res.TotalResistance)
+	return res, nil
+}
+
+// ----- task3: струми КЗ ХПнЕМ -----
+
+type Task3Input struct {
+	// Опори системи, Ом; nil — не задано (значення з прикладу). Нуль —
+	// допустиме значення, наприклад R = 0 для чисто реактивної системи.
+	RSn    *float64 `json:"rSn"`
+	XSn    *float64 `json:"xSn"`
+	RSnMin *float64 `json:"rSnMin"`
+	XSnMin *float64 `json:"xSnMin"`
+
+	Transformer string  `json:"transformer"` // ID з transformerCatalog
+	Uvn         float64 `json:"uvn"`         // кВ
+	Unn         float64 `json:"unn"`         // кВ
+	UkMax       float64 `json:"ukMax"`       // %
+	Snom        float64 `json:"snom"`        // МВ·А
+
+	Length float64 `json:"length"` // км
+	R0     float64 `json:"r0"`     // Ом/км
+	X0     float64 `json:"x0"`     // Ом/км
+}
+
+// withDefaults доповнює незадані параметри з каталогу трансформаторів;
+// опори системи та лінія за замовчуванням — ХПнЕМ з прикладу.
+func (in Task3Input) withDefaults() (Task3Input, error) {
+	for _, f := range []struct {
+		v    **float64
+		def  float64
+		name string
+	}{
+		{&in.RSn, 10.65, "R с.н"},
+		{&in.XSn, 24.02, "X с.н"},
+		{&in.RSnMin, 34.88, "R с.min"},
+		{&in.XSnMin, 65.68, "X с.min"},
+	} {
+		switch {
+		case *f.v == nil:
+			v := f.def
+			*f.v = &v
+		case !(**f.v >= 0) || math.IsInf(**f.v, 0):
+			return in, fmt.Errorf("опір %s має бути невід'ємним числом", f.name)
+		}
+	}
+	if in.Transformer == "" {
+		in.Transformer = defaultTransformer
+	}
+	t, ok := findTransformer(in.Transformer)
+	if !ok {
+		return in, fmt.Errorf("трансформатор %q відсутній у каталозі", in.Transformer)
+	}
+	if in.Uvn <= 0 {
+		in.Uvn = t.Uvn
+	}
+	if in.Unn <= 0 {
+		in.Unn = t.Unn
+	}
+	if in.UkMax <= 0 {
+		in.UkMax = t.ukMax()
+	}
+	if in.Snom <= 0 {
+		in.Snom = t.Snom
+	}
+	if in.Length <= 0 {
+		in.Length = 12.37
+	}
+	if in.R0 <= 0 {
+		in.R0 = 0.64
+	}
+	if in.X0 <= 0 {
+		in.X0 = 0.363
+	}
+	return in, nil
+}
+
+// Task3Result — опори, Ом, та струми КЗ, А. Суфікс 3/2 — трифазне/двофазне
+// КЗ; Sh — шини 10 кВ, зведені до 110 кВ; Shn — шини 10 кВ; Ln — кінець лінії.
+type Task3Result struct {
+	Input Task3Input `json:"input"` // з підставленими значеннями за замовчуванням
+
+	Reactance float64 `json:"reactance"` // Xт, зведений до Uвн
+
+	XSh     float64 `json:"xSh"`
+	ZSh     float64 `json:"zSh"`
+	XShMin  float64 `json:"xShMin"`
+	ZShMin  float64 `json:"zShMin"`
+	ISh3    float64 `json:"iSh3"`
+	ISh2    float64 `json:"iSh2"`
+	IShMin3 float64 `json:"iShMin3"`
+	IShMin2 float64 `json:"iShMin2"`
+
+	Coef float64 `json:"coef"` // kпр = (Uнн/Uвн)²
+
+	RShn     float64 `json:"rShn"`
+	XShn     float64 `json:"xShn"`
+	ZShn     float64 `json:"zShn"`
+	RShnMin  float64 `json:"rShnMin"`
+	XShnMin  float64 `json:"xShnMin"`
+	ZShnMin  float64 `json:"zShnMin"`
+	IShn3    float64 `json:"iShn3"`
+	IShn2    float64 `json:"iShn2"`
+	IShnMin3 float64 `json:"iShnMin3"`
+	IShnMin2 float64 `json:"iShnMin2"`
+
+	RL float64 `json:"rL"` // опори лінії
+	XL float64 `json:"xL"`
+
+	RSumN    float64 `json:"rSumN"`
+	XSumN    float64 `json:"xSumN"`
+	ZSumN    float64 `json:"zSumN"`
+	RSumNMin float64 `json:"rSumNMin"`
+	XSumNMin float64 `json:"xSumNMin"`
+	ZSumNMin float64 `json:"zSumNMin"`
+	Iln3     float64 `json:"iLn3"`
+	Iln2     float64 `json:"iLn2"`
+	IlnMin3  float64 `json:"iLnMin3"`
+	IlnMin2  float64 `json:"iLnMin2"`
+}
+
+func getReactance(ukMax, uvn, snom float64) float64 {
+	return (ukMax * math.Pow(uvn, 2)) / (100 * snom)
+}
+
+func getZ(r, x float64) float64 {
+	return math.Hypot(r, x)
+}
+
+// getI3 — струм трифазного КЗ, А (u в кВ, z в Ом).
+func getI3(u, z float64) float64 {
+	return u * 1000 / (sqrt3 * z)
+}
+
+// getI2 — струм двофазного КЗ з трифазного.
+func getI2(i3 float64) float64 {
+	return i3 * sqrt3 / 2
+}
+
+func getCoef(unn, uvn float64) float64 {
+	return math.Pow(unn, 2) / math.Pow(uvn, 2)
+}
+
+func calculateTask3(in Task3Input) (Task3Result, error) {
+	in, err := in.withDefaults()
+	if err != nil {
+		return Task3Result{}, err
+	}
+	unn, uvn := in.Unn, in.Uvn
+
+	res := Task3Result{Input: in}
+	res.Reactance = getReactance(in.UkMax, uvn, in.Snom)
+
+	rsn, rsnMin := *in.RSn, *in.RSnMin
+	res.XSh = *in.XSn + res.Reactance
+	res.ZSh = getZ(rsn, res.XSh)
+	res.XShMin = *in.XSnMin + res.Reactance
+	res.ZShMin = getZ(rsnMin, res.XShMin)
+	res.ISh3 = getI3(uvn, res.ZSh)
+	res.ISh2 = getI2(res.ISh3)
+	res.IShMin3 = getI3(uvn, res.ZShMin)
+	res.IShMin2 = getI2(res.IShMin3)
+
+	res.Coef = getCoef(unn, uvn)
+	res.RShn = rsn * res.Coef
+	res.XShn = res.XSh * res.Coef
+	res.ZShn = getZ(res.RShn, res.XShn)
+	res.RShnMin = rsnMin * res.Coef
+	res.XShnMin = res.XShMin * res.Coef
+	res.ZShnMin = getZ(res.RShnMin, res.XShnMin)
+	res.IShn3 = getI3(unn, res.ZShn)
+	res.IShn2 = getI2(res.IShn3)
+	res.IShnMin3 = getI3(unn, res.ZShnMin)
+	res.IShnMin2 = getI2(res.IShnMin3)
+
+	res.RL = in.Length * in.R0
+	res.XL = in.Length * in.X0
+	res.RSumN = res.RL + res.RShn
+	res.XSumN = res.XL + res.XShn
+	res.ZSumN = getZ(res.RSumN, res.XSumN)
+	res.RSumNMin = res.RL + res.RShnMin
+	res.XSumNMin = res.XL + res.XShnMin
+	res.ZSumNMin = getZ(res.RSumNMin, res.XSumNMin)
+	res.Iln3 = getI3(unn, res.ZSumN)
+	res.Iln2 = getI2(res.Iln3)
+	res.IlnMin3 = getI3(unn, res.ZSumNMin)
+	res.IlnMin2 = getI2(res.IlnMin3)
+	return res, nil
+}
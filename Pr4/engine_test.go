@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// Приклад з placeholder-ів форми.
+var (
+	exampleTask1 = Task1Input{Current: 2.5, HighVoltage: 10, Time: 2.5, CalculatedLoad: 1300, Hours: 4000, CableType: "ААБ"}
+	exampleTask2 = Task2Input{PowerKz: 200}
+	exampleTask3 = Task3Input{RSn: ptr(10.65), XSn: ptr(24.02), RSnMin: ptr(34.88), XSnMin: ptr(65.68)}
+)
+
+func ptr(v float64) *float64 { return &v }
+
+// golden — значення так, як його показує сторінка. Якщо legacy задано,
+// відображене значення свідомо змінилося відносно старого ланцюжка
+// format → parseFloat, а reason пояснює чому.
+type golden struct {
+	name   string
+	format string // f0 | f1 | f2 | f3 з templateFuncs
+	got    float64
+	want   string
+	legacy string
+	reason string
+}
+
+func checkGolden(t *testing.T, cases []golden) {
+	t.Helper()
+	for _, c := range cases {
+		got := templateFuncs[c.format].(func(float64) string)(c.got)
+		if got != c.want {
+			t.Errorf("%s = %s, want %s", c.name, got, c.want)
+		}
+		if c.legacy == c.want || c.legacy != "" && c.reason == "" {
+			t.Errorf("%s: зміна %s → %s без пояснення", c.name, c.legacy, c.want)
+		}
+	}
+}
+
+func TestTask1Golden(t *testing.T) {
+	res := calculateTask1(exampleTask1)
+	checkGolden(t, []golden{
+		{name: "Iн", format: "f1", got: res.CurrentNormal, want: "37.5"},
+		{name: "Iпа", format: "f0", got: res.CurrentPostAccident, want: "75"},
+		{name: "Jек", format: "f1", got: res.J, want: "1.4"},
+		{name: "sек", format: "f1", got: res.EconomicCrossSection, want: "26.8"},
+		{name: "sтерм", format: "f1", got: res.ThermalStability, want: "43.0"},
+	})
+	if res.Selection == nil {
+		t.Fatalf("кабель не вибрано: %s", res.CableError)
+	}
+	if s := res.Selection.Section; s.S != 50 || s.Idop != 140 {
+		t.Errorf("кабель 3×%g, Iдоп %g А, want 3×50, 140 А", s.S, s.Idop)
+	}
+	// Було «≥ 75.0 А»: Iпа = 2·Iн рахувався з округленого Iн = 37,5 А,
+	// тепер з точного 37,53 А.
+	if got, want := res.Selection.Criteria[2].Required, "1.3·Iдоп ≥ 75.1 А"; got != want {
+		t.Errorf("умова післяаварійного режиму %q, want %q", got, want)
+	}
+}
+
+func TestTask2Golden(t *testing.T) {
+	res, err := calculateTask2(exampleTask2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, []golden{
+		{name: "Xс", format: "f2", got: res.Xs, want: "0.55"},
+		{name: "Xт", format: "f2", got: res.Xt, want: "1.84"},
+		{name: "XΣ", format: "f2", got: res.TotalResistance, want: "2.39"},
+		{name: "Iп0", format: "f1", got: res.InitialCurrentValues, want: "2.5"},
+	})
+}
+
+func TestTask3Golden(t *testing.T) {
+	res, err := calculateTask3(exampleTask3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Старий ланцюжок округлював kпр до 0,009 (замість 0,00915), опори
+	// шин 10 кВ — до 0,01 Ом і брав √3 ≈ 1,73. Через kпр опори на стороні
+	// 10 кВ були занижені на 1,6 %, тож струми — завищені.
+	const coefReason = "kпр більше не округлюється до 0,009, √3 замість 1,73"
+	const sqrt3Reason = "I(2) = I(3)·√3/2 з неокругленого I(3), а не 1,73/2 від цілого"
+	checkGolden(t, []golden{
+		{name: "Xт", format: "f0", got: res.Reactance, want: "233"},
+		{name: "kпр", format: "f3", got: res.Coef, want: "0.009"},
+
+		{name: "Iш(3)", format: "f0", got: res.ISh3, want: "258"},
+		{name: "Iш(2)", format: "f0", got: res.ISh2, want: "224", legacy: "223", reason: sqrt3Reason},
+		{name: "Iш.min(3)", format: "f0", got: res.IShMin3, want: "221"},
+		{name: "Iш.min(2)", format: "f0", got: res.IShMin2, want: "191"},
+
+		{name: "Iш.н(3)", format: "f0", got: res.IShn3, want: "2698", legacy: "2753", reason: coefReason},
+		{name: "Iш.н(2)", format: "f0", got: res.IShn2, want: "2337", legacy: "2381", reason: coefReason},
+		{name: "Iш.н.min(3)", format: "f0", got: res.IShnMin3, want: "2308", legacy: "2355", reason: coefReason},
+		{name: "Iш.н.min(2)", format: "f0", got: res.IShnMin2, want: "1999", legacy: "2037", reason: coefReason},
+
+		{name: "Iл.н(3)", format: "f0", got: res.Iln3, want: "603", legacy: "605", reason: coefReason},
+		{name: "Iл.н(2)", format: "f0", got: res.Iln2, want: "522", legacy: "523", reason: coefReason},
+		{name: "Iл.н.min(3)", format: "f0", got: res.IlnMin3, want: "580", legacy: "582", reason: coefReason},
+		{name: "Iл.н.min(2)", format: "f0", got: res.IlnMin2, want: "502", legacy: "503", reason: coefReason},
+	})
+}
+
+// Незадані опори системи беруться з прикладу, а не стають нулями.
+func TestTask3Defaults(t *testing.T) {
+	got, err := calculateTask3(Task3Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := calculateTask3(exampleTask3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Input, want.Input = Task3Input{}, Task3Input{}
+	if got != want {
+		t.Errorf("calculateTask3({}) = %+v\nwant %+v", got, want)
+	}
+}
+
+// Нуль — задане значення, а не «не задано»; від'ємні опори відхиляються.
+func TestTask3SystemImpedance(t *testing.T) {
+	in := exampleTask3
+	in.RSn = ptr(0)
+	res, err := calculateTask3(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ZSh != res.XSh || res.RShn != 0 {
+		t.Errorf("R с.н = 0: Zш = %g, Xш = %g, Rш.н = %g", res.ZSh, res.XSh, res.RShn)
+	}
+
+	for _, v := range []float64{-1, math.NaN(), math.Inf(1)} {
+		in := exampleTask3
+		in.XSnMin = ptr(v)
+		if _, err := calculateTask3(in); err == nil {
+			t.Errorf("X с.min = %g: очікується помилка", v)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Equipment — апарат або комплектний розподільчий пристрій з номінальним
+// струмом динамічної стійкості (амплітуда граничного наскрізного струму).
+type Equipment struct {
+	ID   string  `json:"id"`
+	Kind string  `json:"kind"`
+	Unom float64 `json:"unom"` // кВ
+	Inom float64 `json:"inom"` // А
+	Idyn float64 `json:"idyn"` // кА, iдин
+}
+
+var equipmentCatalog = []Equipment{
+	{ID: "ВМП-10-630-20", Kind: "Вимикач маломасляний", Unom: 10, Inom: 630, Idyn: 52},
+	{ID: "ВМП-10-1000-20", Kind: "Вимикач маломасляний", Unom: 10, Inom: 1000, Idyn: 52},
+	{ID: "ВМПЭ-10-1600-31,5", Kind: "Вимикач маломасляний", Unom: 10, Inom: 1600, Idyn: 80},
+	{ID: "BB/TEL-10-12,5/630", Kind: "Вимикач вакуумний", Unom: 10, Inom: 630, Idyn: 32},
+	{ID: "BB/TEL-10-20/1000", Kind: "Вимикач вакуумний", Unom: 10, Inom: 1000, Idyn: 51},
+	{ID: "BB/TEL-10-31,5/2000", Kind: "Вимикач вакуумний", Unom: 10, Inom: 2000, Idyn: 81},
+	{ID: "РВЗ-10/630", Kind: "Роз'єднувач", Unom: 10, Inom: 630, Idyn: 52},
+	{ID: "КРУ К-59", Kind: "Шини КРУ", Unom: 10, Inom: 1000, Idyn: 51},
+	{ID: "КРУ К-104М", Kind: "Шини КРУ", Unom: 10, Inom: 1600, Idyn: 81},
+	{ID: "КСО-298", Kind: "Шини КСО", Unom: 10, Inom: 630, Idyn: 51},
+	{ID: "ВМТ-110Б-20/1000", Kind: "Вимикач маломасляний", Unom: 110, Inom: 1000, Idyn: 52},
+	{ID: "ВГТ-110-40/2500", Kind: "Вимикач елегазовий", Unom: 110, Inom: 2500, Idyn: 102},
+	{ID: "РНДЗ-110/1000", Kind: "Роз'єднувач", Unom: 110, Inom: 1000, Idyn: 80},
+}
+
+func findEquipment(id string) (Equipment, bool) {
+	for _, e := range equipmentCatalog {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Equipment{}, false
+}
+
+// DynamicCheck — перевірка апарата: номінальна напруга не нижча за
+// напругу мережі (Uном ≥ Uмережі) та динамічна стійкість (iуд ≤ iдин).
+type DynamicCheck struct {
+	Equipment Equipment `json:"equipment"`
+	Unet      float64   `json:"unet"` // номінальна напруга мережі, кВ
+	Ish       float64   `json:"ish"`  // кА
+	VoltageOK bool      `json:"voltageOk"`
+	DynamicOK bool      `json:"dynamicOk"`
+	OK        bool      `json:"ok"`
+}
+
+// Шкала номінальних напруг мереж, кВ.
+var nominalVoltages = []float64{0.38, 0.66, 3, 6, 10, 20, 35, 110, 150, 220, 330, 500, 750}
+
+// networkVoltage — номінальна напруга мережі для напруги вузла kv (середньої
+// номінальної, наприклад 10,5; 11; 115): найменший клас, для якого kv не
+// перевищує 1,1·Uном. Вузол понад шкалу повертається як є.
+func networkVoltage(kv float64) float64 {
+	for _, u := range nominalVoltages {
+		if kv <= 1.1*u+1e-9 {
+			return u
+		}
+	}
+	return kv
+}
+
+// shockFactor — ударний коефіцієнт kуд = 1 + e^(-0,01/Ta), Ta = X/(ω·R).
+// При R = 0 стала часу нескінченна, kуд = 2; Ta тоді повертається як 0.
+func shockFactor(r, x float64) (ta, ksh float64) {
+	if r <= 0 {
+		return 0, 2
+	}
+	ta = x / (2 * math.Pi * 50 * r)
+	return ta, 1 + math.Exp(-0.01/ta)
+}
+
+// shockCurrent — ударний струм iуд = √2·kуд·Iк, кА (Iк в амперах).
+func shockCurrent(ksh, ik float64) float64 {
+	return math.Sqrt2 * ksh * ik / 1000
+}
+
+func checkDynamic(ids []string, kv, ish float64) ([]DynamicCheck, error) {
+	unet := networkVoltage(kv)
+	var checks []DynamicCheck
+	for _, id := range ids {
+		e, ok := findEquipment(id)
+		if !ok {
+			return nil, fmt.Errorf("апарат %q відсутній у каталозі", id)
+		}
+		c := DynamicCheck{Equipment: e, Unet: unet, Ish: ish, VoltageOK: e.Unom >= unet, DynamicOK: ish <= e.Idyn}
+		c.OK = c.VoltageOK && c.DynamicOK
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestNetworkVoltage(t *testing.T) {
+	tests := []struct{ kv, want float64 }{
+		{0.4, 0.38}, {6.3, 6}, {10, 10}, {10.5, 10}, {11, 10}, {37, 35}, {115, 110}, {121, 110}, {230, 220},
+	}
+	for _, tt := range tests {
+		if got := networkVoltage(tt.kv); got != tt.want {
+			t.Errorf("networkVoltage(%g) = %g, want %g", tt.kv, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDynamic(t *testing.T) {
+	tests := []struct {
+		id               string
+		kv, ish          float64
+		voltage, dynamic bool
+	}{
+		{"BB/TEL-10-20/1000", 11, 20, true, true},
+		{"BB/TEL-10-20/1000", 11, 60, true, false},
+		// 10 кВ вимикач на шинах 115 кВ не проходить за напругою
+		{"BB/TEL-10-20/1000", 115, 20, false, true},
+		{"ВГТ-110-40/2500", 115, 20, true, true},
+		{"ВГТ-110-40/2500", 11, 20, true, true},
+	}
+	for _, tt := range tests {
+		checks, err := checkDynamic([]string{tt.id}, tt.kv, tt.ish)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := checks[0]
+		if c.VoltageOK != tt.voltage || c.DynamicOK != tt.dynamic || c.OK != (tt.voltage && tt.dynamic) {
+			t.Errorf("%s на %g кВ, iуд %g кА: напруга %v, динаміка %v, OK %v; want %v, %v",
+				tt.id, tt.kv, tt.ish, c.VoltageOK, c.DynamicOK, c.OK, tt.voltage, tt.dynamic)
+		}
+	}
+	if _, err := checkDynamic([]string{"немає"}, 11, 1); err == nil {
+		t.Error("невідомий апарат має давати помилку")
+	}
+}
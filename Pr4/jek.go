@@ -0,0 +1,62 @@
+package main
+
+import "fmt"
+
+// Нормативна економічна густина струму Jек, А/мм² (ПУЕ, табл. 1.3.36)
+// залежно від провідника та річної кількості годин використання
+// максимуму навантаження Tmax.
+
+type Conductor struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Material   string     `json:"material"`   // al | cu
+	Insulation string     `json:"insulation"` // bare | paper | plastic
+	J          [3]float64 `json:"j"`          // для діапазонів jekBands
+}
+
+// Діапазони Tmax, год: до 3000 включно, понад 3000 до 5000 включно,
+// понад 5000. Значення до 1000 год віднесено до першого діапазону.
+var jekBands = [3]string{"до 3000 год", "понад 3000 до 5000 год", "понад 5000 год"}
+
+var conductors = []Conductor{
+	{ID: "bare-cu", Name: "Неізольовані проводи та шини, мідні", Material: "cu", Insulation: "bare", J: [3]float64{2.5, 2.1, 1.8}},
+	{ID: "bare-al", Name: "Неізольовані проводи та шини, алюмінієві", Material: "al", Insulation: "bare", J: [3]float64{1.3, 1.1, 1.0}},
+	{ID: "paper-cu", Name: "Кабелі з паперовою, проводи з гумовою та ПВХ ізоляцією, мідні жили", Material: "cu", Insulation: "paper", J: [3]float64{3.0, 2.5, 2.0}},
+	{ID: "paper-al", Name: "Кабелі з паперовою, проводи з гумовою та ПВХ ізоляцією, алюмінієві жили", Material: "al", Insulation: "paper", J: [3]float64{1.6, 1.4, 1.2}},
+	{ID: "plastic-cu", Name: "Кабелі з гумовою та пластмасовою ізоляцією, мідні жили", Material: "cu", Insulation: "plastic", J: [3]float64{3.5, 3.1, 2.7}},
+	{ID: "plastic-al", Name: "Кабелі з гумовою та пластмасовою ізоляцією, алюмінієві жили", Material: "al", Insulation: "plastic", J: [3]float64{1.9, 1.7, 1.6}},
+}
+
+func jekBand(hours float64) int {
+	switch {
+	case hours <= 3000:
+		return 0
+	case hours <= 5000:
+		return 1
+	}
+	return 2
+}
+
+func findConductor(id string) (Conductor, bool) {
+	for _, c := range conductors {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Conductor{}, false
+}
+
+// conductorFor — рядок таблиці для матеріалу й ізоляції кабелю з каталогу.
+func conductorFor(material, insulation string) (Conductor, error) {
+	for _, c := range conductors {
+		if c.Material == material && c.Insulation == insulation {
+			return c, nil
+		}
+	}
+	return Conductor{}, fmt.Errorf("немає нормативу Jек для провідника %s/%s", material, insulation)
+}
+
+// determineJ повертає Jек для провідника та Tmax.
+func determineJ(c Conductor, hours float64) float64 {
+	return c.J[jekBand(hours)]
+}
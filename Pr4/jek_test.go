@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestDetermineJBoundaries(t *testing.T) {
+	// Межі діапазонів включні: 3000 і 5000 год ще належать нижчому.
+	hours := []float64{1000, 3000, 3000.0001, 5000, 5001}
+	want := map[string][5]float64{
+		"bare-cu":    {2.5, 2.5, 2.1, 2.1, 1.8},
+		"bare-al":    {1.3, 1.3, 1.1, 1.1, 1.0},
+		"paper-cu":   {3.0, 3.0, 2.5, 2.5, 2.0},
+		"paper-al":   {1.6, 1.6, 1.4, 1.4, 1.2},
+		"plastic-cu": {3.5, 3.5, 3.1, 3.1, 2.7},
+		"plastic-al": {1.9, 1.9, 1.7, 1.7, 1.6},
+	}
+	if len(want) != len(conductors) {
+		t.Fatalf("таблиця тесту має %d провідників, каталог — %d", len(want), len(conductors))
+	}
+	for id, js := range want {
+		c, ok := findConductor(id)
+		if !ok {
+			t.Fatalf("провідник %s не знайдено", id)
+		}
+		for i, h := range hours {
+			if got := determineJ(c, h); got != js[i] {
+				t.Errorf("%s, Tmax = %g год: Jек = %g, want %g", id, h, got, js[i])
+			}
+		}
+	}
+}
+
+func TestJekBand(t *testing.T) {
+	tests := []struct {
+		hours float64
+		want  int
+	}{
+		{0, 0}, {1000, 0}, {3000, 0}, {3000.0001, 1}, {5000, 1}, {5001, 2}, {8760, 2},
+	}
+	for _, tt := range tests {
+		if got := jekBand(tt.hours); got != tt.want {
+			t.Errorf("jekBand(%g) = %d, want %d", tt.hours, got, tt.want)
+		}
+	}
+}
@@ -1,48 +1,78 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type PageData struct {
+	// Введені значення зберігаються як рядки, щоб повернути їх у форму без змін
 	Current        string
 	HighVoltage    string
 	Time           string
 	CalculatedLoad string
 	Hours          string
+	CableType      string
+	Conductor      string
 	PowerKz        string
+	Transformer2   string
+	Ucn            string
+	Uk             string
+	Snom2          string
 	RSn            string
 	XSn            string
 	RSnMin         string
 	XSnMin         string
+	Transformer3   string
+	Uvn            string
+	Unn            string
+	UkMax          string
+	Snom3          string
+	LineLength     string
+	LineR0         string
+	LineX0         string
+	LoadCurrent    string
+	CTRatio        string
+	Kn             string
+	Ksz            string
+	Kv             string
+	KnCutoff       string
+
+	Task1      *Task1Result
+	Task2      *Task2Result
+	Task2Error string
+	Task3      *Task3Result
+	Task3Error string
+	Relay      *RelayResult
+	RelayError string
+
+	Cables       []CableType
+	Conductors   []Conductor
+	Transformers []TransformerType
+
+	NetworkJSON  string
+	Network      *NetworkReport
+	NetworkError string
+	NetworkSVG   template.HTML // однолінійна схема, згенерована diagram.go
+	Equipment    []Equipment
+}
 
-	ResultCurrentNormal        string
-	ResultCurrentPostAccident  string
-	ResultEconomicCrossSection string
-	ResultThermalStability     string
-	Xs                         string
-	Xt                         string
-	TotalResistance            string
-	InitialCurrentValues       string
-	ISh3                       string
-	ISh2                       string
-	IShMin3                    string
-	IShMin2                    string
-	IShn3                      string
-	IShn2                      string
-	IShnMin3                   string
-	IShnMin2                   string
-	Iln3                       string
-	Iln2                       string
-	IlnMin3                    string
-	IlnMin2                    string
+// templateFuncs — єдине місце, де числа перетворюються на текст.
+var templateFuncs = template.FuncMap{
+	"hypot": math.Hypot,
+	"f0":    func(v float64) string { return strconv.Itoa(int(math.Round(v))) },
+	"f1":    func(v float64) string { return fmt.Sprintf("%.1f", v) },
+	"f2":    func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"f3":    func(v float64) string { return fmt.Sprintf("%.3f", v) },
 }
 
-var tmpl = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+var tmpl = template.Must(template.New("index").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
 <html lang="uk">
 <head>
     <meta charset="UTF-8">
@@ -54,14 +84,24 @@ var tmpl = template.Must(template.New("index").Parse(`<!DOCTYPE html>
         input { padding: 8px; }
         button { padding: 10px 14px; cursor: pointer; }
         p { margin: 10px 0; }
+        form.wide { max-width: none; }
+        .fields { display: grid; gap: 10px; max-width: 420px; }
+        textarea { font-family: monospace; font-size: 13px; padding: 8px; }
+        table { border-collapse: collapse; margin-top: 12px; }
+        th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: right; }
+        .error { color: #b00020; font-weight: bold; }
+        .ok { color: #1b5e20; font-weight: bold; }
+        figure.diagram { margin: 12px 0; overflow-x: auto; }
+        tr.governing td { font-weight: bold; background: #fff3cd; }
     </style>
 </head>
 <body>
     <h1>Розрахунок струму трифазного КЗ, струму однофазного КЗ, та перевірки на термічну та динамічну стійкість.</h1>
 
+    <form method="post" action="/all" class="wide">
     <section>
         <h2>Внесіть дані для вибору кабеля для живлення двотрансформаторної підстанції системи внутрішнього електропостачання підприємства:</h2>
-        <form method="post" action="/task1">
+        <div class="fields">
             <label for="current">I<sub>к</sub>, кА:</label>
             <input type="number" id="current" name="current" placeholder="2,5" step="any" value="{{.Current}}">
 
@@ -77,33 +117,73 @@ var tmpl = template.Must(template.New("index").Parse(`<!DOCTYPE html>
             <label for="hours">Т<sub>M</sub>, год:</label>
             <input type="number" id="hours" name="hours" placeholder="4000" step="any" value="{{.Hours}}">
 
-            <button type="submit">Submit</button>
-        </form>
+            <label for="cable-type">Тип кабелю:</label>
+            <select id="cable-type" name="cable_type">
+                {{range .Cables}}<option value="{{.ID}}"{{if eq .ID $.CableType}} selected{{end}}>{{.ID}} — {{.Description}}</option>{{end}}
+            </select>
+
+            <label for="conductor">Провідник для J<sub>ек</sub>:</label>
+            <select id="conductor" name="conductor">
+                <option value="">за типом кабелю</option>
+                {{range .Conductors}}<option value="{{.ID}}"{{if eq .ID $.Conductor}} selected{{end}}>{{.Name}}</option>{{end}}
+            </select>
+
+            <button type="submit" formaction="/task1">Submit</button>
+        </div>
         <div>
-            <p><span>Розрахунковий струм для нормального режима: </span><span>{{.ResultCurrentNormal}}</span><span> А</span></p>
-            <p><span>Розрахунковий струм для післяаварійного режима: </span><span>{{.ResultCurrentPostAccident}}</span><span> А</span></p>
-            <p><span>Для внутрізаводської мережі вибираємо броньовані кабелі з паперовою ізоляцією в алюмінієвій оболонці типу ААБ з економічним перерізом : </span><span>{{.ResultEconomicCrossSection}}</span><span> мм<sup>2</sup></span></p>
-            <p><span>Вибираємо кабель ААБ 10 3×25 з допустимим струмом 90 А. Однак за термічною стійкістю до дії струмів КЗ:</span><span>{{.ResultThermalStability}}</span><span> мм<sup>2</sup></span></p>
+            {{with .Task1}}
+            <p><span>Розрахунковий струм для нормального режима: </span><span>{{f1 .CurrentNormal}}</span><span> А</span></p>
+            <p><span>Розрахунковий струм для післяаварійного режима: </span><span>{{f0 .CurrentPostAccident}}</span><span> А</span></p>
+            <p><span>Економічна густина струму J<sub>ек</sub>: </span><span>{{printf "%g" .J}}</span><span> А/мм<sup>2</sup> ({{.Conductor.Name}}, T<sub>max</sub> {{.JBand}})</span></p>
+            <p><span>Економічний переріз: </span><span>{{f1 .EconomicCrossSection}}</span><span> мм<sup>2</sup></span></p>
+            <p><span>Мінімальний переріз за термічною стійкістю до дії струмів КЗ: </span><span>{{f1 .ThermalStability}}</span><span> мм<sup>2</sup></span></p>
+            {{if .CableError}}<p class="error">{{.CableError}}</p>{{end}}
+            {{with .Selection}}
+            <table>
+                <tr><th>Критерій</th><th>Умова</th><th>Мін. стандартний переріз, мм<sup>2</sup></th></tr>
+                {{range .Criteria}}<tr{{if .Governing}} class="governing"{{end}}><td>{{.Name}}</td><td>{{.Required}}</td><td>{{printf "%g" .Section}}</td></tr>{{end}}
+            </table>
+            <p><span>Вибираємо кабель {{.Cable.ID}} {{printf "%g" .Cable.Unom}} 3×{{printf "%g" .Section.S}} з допустимим струмом {{printf "%g" .Section.Idop}} А. Визначальний критерій: {{.GoverningNames}}.</span></p>
+            {{end}}
+            {{end}}
         </div>
     </section>
 
     <section>
         <h2>Внесіть дані для визначення струму КЗ на шинах 10 кВ ГПП:</h2>
-        <form method="post" action="/task2">
+        <div class="fields">
             <label for="power-kz">S<sub>к</sub>, МВ*А:</label>
             <input type="number" id="power-kz" name="power_kz" placeholder="200" step="any" value="{{.PowerKz}}">
-            <button type="submit">Submit</button>
-        </form>
+
+            <label for="t2-transformer">Трансформатор:</label>
+            <select id="t2-transformer" name="t2_transformer">
+                {{range .Transformers}}<option value="{{.ID}}"{{if eq .ID $.Transformer2}} selected{{end}}>{{.ID}}</option>{{end}}
+            </select>
+
+            <label for="ucn">U<sub>ср.ном</sub>, кВ:</label>
+            <input type="number" id="ucn" name="ucn" placeholder="10,5" step="any" value="{{.Ucn}}">
+
+            <label for="uk">u<sub>к</sub>, % (порожнє — з каталогу):</label>
+            <input type="number" id="uk" name="uk" placeholder="10,5" step="any" value="{{.Uk}}">
+
+            <label for="t2-snom">S<sub>ном.т</sub>, МВ*А (порожнє — з каталогу):</label>
+            <input type="number" id="t2-snom" name="t2_snom" placeholder="6,3" step="any" value="{{.Snom2}}">
+            <button type="submit" formaction="/task2">Submit</button>
+        </div>
         <div>
-            <p><span>Опори елементів заступної схеми: Х<sub>с</sub>=</span><span>{{.Xs}}</span><span> Ом,</span><span>Х<sub>т</sub>=</span><span>{{.Xt}}</span><span> Ом</span></p>
-            <p><span>Сумарний опір для точкі К1: </span><span>{{.TotalResistance}}</span><span> Ом</span></p>
-            <p><span>Початкові значення струму трифазного КЗ: </span><span>{{.InitialCurrentValues}}</span><span> кА</span></p>
+            {{if .Task2Error}}<p class="error">{{.Task2Error}}</p>{{end}}
+            {{with .Task2}}
+            {{with .Input}}<p><span>Параметри: {{.Transformer}}, U<sub>ср.ном</sub> = {{printf "%g" .Ucn}} кВ, u<sub>к</sub> = {{printf "%g" .Uk}} %, S<sub>ном.т</sub> = {{printf "%g" .Snom}} МВ*А</span></p>{{end}}
+            <p><span>Опори елементів заступної схеми: Х<sub>с</sub>=</span><span>{{f2 .Xs}}</span><span> Ом,</span><span>Х<sub>т</sub>=</span><span>{{f2 .Xt}}</span><span> Ом</span></p>
+            <p><span>Сумарний опір для точкі К1: </span><span>{{f2 .TotalResistance}}</span><span> Ом</span></p>
+            <p><span>Початкові значення струму трифазного КЗ: </span><span>{{f1 .InitialCurrentValues}}</span><span> кА</span></p>
+            {{end}}
         </div>
     </section>
 
     <section>
         <h2>Внесіть дані для визначення струмів КЗ Хмельницьких північних електричних мереж (ХПнЕМ):</h2>
-        <form method="post" action="/task3">
+        <div class="fields">
             <label for="r-sn">R<sub>с.н</sub>, Ом:</label>
             <input type="number" id="r-sn" name="r_sn" placeholder="10,65" step="any" value="{{.RSn}}">
 
@@ -116,281 +196,380 @@ var tmpl = template.Must(template.New("index").Parse(`<!DOCTYPE html>
             <label for="x-s-min">X<sub>с.min</sub>, Ом:</label>
             <input type="number" id="x-s-min" name="x_s_min" placeholder="65,68" step="any" value="{{.XSnMin}}">
 
-            <button type="submit">Submit</button>
-        </form>
-        <div>
-            <p><span>Cтруми трифазного та двофазного КЗ на шинах 10 кВ в норм. та мін. режимах, приведені до напригу 110 кВ: I<sub>ш</sub><sup>(3)</sup>=</span><span>{{.ISh3}}</span><span> A, </span><span>I<sub>ш</sub><sup>(2)</sup>=</span><span>{{.ISh2}}</span><span> A, </span><span>I<sub>ш.min</sub><sup>(3)</sup>=</span><span>{{.IShMin3}}</span><span> A, </span><span>I<sub>ш.min</sub><sup>(2)</sup>=</span><span>{{.IShMin2}}</span><span> A</span></p>
-            <p><span>Дійсні струми трифазного та двофазного КЗ на шинах 10 кВ в норм. та мін. режимах: I<sub>ш.н</sub><sup>(3)</sup>=</span><span>{{.IShn3}}</span><span> A, </span><span>I<sub>ш.н</sub><sup>(2)</sup>=</span><span>{{.IShn2}}</span><span> A, </span><span>I<sub>ш.н.min</sub><sup>(3)</sup>=</span><span>{{.IShnMin3}}</span><span> A, </span><span>I<sub>ш.н.min</sub><sup>(2)</sup>=</span><span>{{.IShnMin2}}</span><span> A</span></p>
-            <p><span>Струми трифазного та двофазного КЗ в точці 10 в норм. та мін. режимах: I<sub>л.н</sub><sup>(3)</sup>=</span><span>{{.Iln3}}</span><span> A, </span><span>I<sub>л.н</sub><sup>(2)</sup>=</span><span>{{.Iln2}}</span><span> A, </span><span>I<sub>л.н.min</sub><sup>(3)</sup>=</span><span>{{.IlnMin3}}</span><span> A, </span><span>I<sub>л.н.min</sub><sup>(2)</sup>=</span><span>{{.IlnMin2}}</span><span> A</span></p>
-        </div>
-    </section>
-</body>
-</html>`))
-
-func parseFloat(val string) float64 {
-	f, _ := strconv.ParseFloat(val, 64)
-	return f
-}
+            <label for="t3-transformer">Трансформатор:</label>
+            <select id="t3-transformer" name="t3_transformer">
+                {{range .Transformers}}<option value="{{.ID}}"{{if eq .ID $.Transformer3}} selected{{end}}>{{.ID}}</option>{{end}}
+            </select>
 
-func format1(v float64) string { return fmt.Sprintf("%.1f", v) }
-func format2(v float64) string { return fmt.Sprintf("%.2f", v) }
-func format3(v float64) string { return fmt.Sprintf("%.3f", v) }
-func round(v float64) string   { return strconv.Itoa(int(math.Round(v))) }
+            <label for="uvn">U<sub>вн</sub>, кВ (порожнє — з каталогу):</label>
+            <input type="number" id="uvn" name="uvn" placeholder="115" step="any" value="{{.Uvn}}">
 
-func getCalculatedCurrentNormal(s, u float64) string {
-	result := (s / 2) / (math.Sqrt(3) * u)
-	return format1(result)
-}
+            <label for="unn">U<sub>нн</sub>, кВ (порожнє — з каталогу):</label>
+            <input type="number" id="unn" name="unn" placeholder="11" step="any" value="{{.Unn}}">
 
-func getCalculatedCurrentPostAccident(i float64) string {
-	return round(2 * i)
-}
+            <label for="uk-max">u<sub>к.max</sub>, % (порожнє — з каталогу):</label>
+            <input type="number" id="uk-max" name="uk_max" placeholder="11,1" step="any" value="{{.UkMax}}">
 
-func getEconomicCrossSection(calculatedCurrentNormal, j float64) string {
-	result := calculatedCurrentNormal / j
-	return format1(result)
-}
+            <label for="t3-snom">S<sub>ном.т</sub>, МВ*А (порожнє — з каталогу):</label>
+            <input type="number" id="t3-snom" name="t3_snom" placeholder="6,3" step="any" value="{{.Snom3}}">
 
-func determineJ(hours float64) float64 {
-	if hours > 3000 && hours < 5000 {
-		return 1.4
-	} else if hours >= 1000 && hours < 3000 {
-		return 1.6
-	}
-	return 1.2
-}
+            <label for="line-length">Довжина лінії, км:</label>
+            <input type="number" id="line-length" name="line_length" placeholder="12,37" step="any" value="{{.LineLength}}">
 
-func getThermalStability(current, time float64) string {
-	result := (current * 1000 * math.Sqrt(time)) / 92
-	return format1(result)
-}
+            <label for="line-r0">r<sub>0</sub>, Ом/км:</label>
+            <input type="number" id="line-r0" name="line_r0" placeholder="0,64" step="any" value="{{.LineR0}}">
 
-func getXs(u, s float64) string {
-	result := math.Pow(u, 2) / s
-	return format2(result)
-}
+            <label for="line-x0">x<sub>0</sub>, Ом/км:</label>
+            <input type="number" id="line-x0" name="line_x0" placeholder="0,363" step="any" value="{{.LineX0}}">
 
-func getXt(ucn, uk, s float64) string {
-	result := (uk / 100) * (math.Pow(ucn, 2) / s)
-	return format2(result)
-}
+            <fieldset>
+                <legend>Релейний захист фідера (необов'язково)</legend>
+                <label for="load-current">I<sub>роб.max</sub>, А:</label>
+                <input type="number" id="load-current" name="load_current" placeholder="120" step="any" value="{{.LoadCurrent}}">
 
-func getTotalResistance(calculatedXs, calculatedXt float64) float64 {
-	return calculatedXs + calculatedXt
-}
+                <label for="ct-ratio">n<sub>т</sub> (ТС):</label>
+                <input type="number" id="ct-ratio" name="ct_ratio" placeholder="40" step="any" value="{{.CTRatio}}">
 
-func getInitialCurrentValues(ucn, totalResistance float64) string {
-	result := ucn / (math.Sqrt(3) * totalResistance)
-	return format1(result)
-}
+                <label for="kn">k<sub>н</sub> МСЗ:</label>
+                <input type="number" id="kn" name="kn" placeholder="1,2" step="any" value="{{.Kn}}">
 
-func getReactance(ukMax float64) string {
-	result := (ukMax * math.Pow(115, 2)) / (100 * 6.3)
-	return round(result)
-}
+                <label for="ksz">k<sub>сзп</sub>:</label>
+                <input type="number" id="ksz" name="ksz" placeholder="1,5" step="any" value="{{.Ksz}}">
 
-func getXSh(XSn, calculatedReactance float64) float64 {
-	return XSn + calculatedReactance
-}
+                <label for="kv">k<sub>п</sub>:</label>
+                <input type="number" id="kv" name="kv" placeholder="0,85" step="any" value="{{.Kv}}">
 
-func getZSh(calculatedXSh float64) string {
-	result := math.Sqrt(math.Pow(10.65, 2) + math.Pow(calculatedXSh, 2))
-	return format1(result)
-}
+                <label for="kn-cutoff">k<sub>н</sub> відсічки:</label>
+                <input type="number" id="kn-cutoff" name="kn_cutoff" placeholder="1,3" step="any" value="{{.KnCutoff}}">
+            </fieldset>
 
-func getXShMin(XSnMin, calculatedReactance float64) float64 {
-	return XSnMin + calculatedReactance
-}
+            <button type="submit" formaction="/task3">Submit</button>
+        </div>
+        <div>
+            {{if .Task3Error}}<p class="error">{{.Task3Error}}</p>{{end}}
+            {{with .Task3}}
+            {{with .Input}}<p><span>Параметри: {{.Transformer}}, {{printf "%g" .Uvn}}/{{printf "%g" .Unn}} кВ, u<sub>к.max</sub> = {{printf "%g" .UkMax}} %, S<sub>ном.т</sub> = {{printf "%g" .Snom}} МВ*А; лінія {{printf "%g" .Length}} км, r<sub>0</sub> = {{printf "%g" .R0}}, x<sub>0</sub> = {{printf "%g" .X0}} Ом/км</span></p>{{end}}
+            <p><span>Cтруми трифазного та двофазного КЗ на шинах 10 кВ в норм. та мін. режимах, приведені до напруги {{printf "%g" .Input.Uvn}} кВ: I<sub>ш</sub><sup>(3)</sup>=</span><span>{{f0 .ISh3}}</span><span> A, </span><span>I<sub>ш</sub><sup>(2)</sup>=</span><span>{{f0 .ISh2}}</span><span> A, </span><span>I<sub>ш.min</sub><sup>(3)</sup>=</span><span>{{f0 .IShMin3}}</span><span> A, </span><span>I<sub>ш.min</sub><sup>(2)</sup>=</span><span>{{f0 .IShMin2}}</span><span> A</span></p>
+            <p><span>Дійсні струми трифазного та двофазного КЗ на шинах 10 кВ в норм. та мін. режимах: I<sub>ш.н</sub><sup>(3)</sup>=</span><span>{{f0 .IShn3}}</span><span> A, </span><span>I<sub>ш.н</sub><sup>(2)</sup>=</span><span>{{f0 .IShn2}}</span><span> A, </span><span>I<sub>ш.н.min</sub><sup>(3)</sup>=</span><span>{{f0 .IShnMin3}}</span><span> A, </span><span>I<sub>ш.н.min</sub><sup>(2)</sup>=</span><span>{{f0 .IShnMin2}}</span><span> A</span></p>
+            <p><span>Струми трифазного та двофазного КЗ в точці 10 в норм. та мін. режимах: I<sub>л.н</sub><sup>(3)</sup>=</span><span>{{f0 .Iln3}}</span><span> A, </span><span>I<sub>л.н</sub><sup>(2)</sup>=</span><span>{{f0 .Iln2}}</span><span> A, </span><span>I<sub>л.н.min</sub><sup>(3)</sup>=</span><span>{{f0 .IlnMin3}}</span><span> A, </span><span>I<sub>л.н.min</sub><sup>(2)</sup>=</span><span>{{f0 .IlnMin2}}</span><span> A</span></p>
+            {{end}}
+            {{if .RelayError}}<p class="error">{{.RelayError}}</p>{{end}}
+            {{with .Relay}}
+            <table>
+                <tr><th>Захист</th><th>I<sub>сз</sub>, А</th><th>I<sub>ср</sub>, А</th><th>I<sub>КЗ.min</sub>, А</th><th>k<sub>ч</sub></th><th>k<sub>ч.доп</sub></th><th></th></tr>
+                <tr><td>МСЗ (k<sub>н</sub>·k<sub>сзп</sub>/k<sub>п</sub>·I<sub>роб.max</sub>; I<sub>л.н.min</sub><sup>(2)</sup>)</td>{{template "relay" .Overcurrent}}</tr>
+                <tr><td>Струмова відсічка (k<sub>н</sub>·I<sub>л.н</sub><sup>(3)</sup>; I<sub>ш.н.min</sub><sup>(2)</sup>)</td>{{template "relay" .Cutoff}}</tr>
+            </table>
+            {{end}}
+        </div>
+    </section>
 
-func getZShMin(RSnMin, calculatedXShMin float64) string {
-	result := math.Sqrt(math.Pow(RSnMin, 2) + math.Pow(calculatedXShMin, 2))
-	return format1(result)
-}
+    <p class="actions">
+        <button type="submit" formaction="/all">Обчислити все</button>
+        <button type="submit" formaction="/reset" formnovalidate>Скинути</button>
+    </p>
+    </form>
 
-func getISh3(calculatedZSh float64) string {
-	result := (115 * 1000) / (1.73 * calculatedZSh)
-	return round(result)
-}
+    <section>
+        <h2>Довільна мережа: розрахунок струмів КЗ у заданих точках</h2>
+        <p>Опишіть вузли (id, напруга kv), елементи (source — система з r, x, rMin, xMin в омах або sk, skMin в МВ*А; transformer — snom, МВ*А, uk, %, pk, кВт; line — length, км, r0, x0, Ом/км) та точки КЗ. Елементи з "offInMin": true не враховуються в мінімальному режимі. Для несиметричних КЗ можна задати r2, x2 (зворотна послідовність), rZero, xZero (нульова послідовність; для ліній — Ом/км) та схему з'єднання трансформатора connection (YNd, Dyn, YNyn…).</p>
+        <form method="post" action="/network" class="wide">
+            <textarea name="network" rows="18" spellcheck="false">{{.NetworkJSON}}</textarea>
+            <button type="submit">Розрахувати</button>
+            <button type="submit" formaction="/network/svg">Завантажити схему SVG</button>
+        </form>
+        {{if .NetworkError}}<p class="error">{{.NetworkError}}</p>{{end}}
+        {{with .NetworkSVG}}<figure class="diagram">{{.}}</figure>{{end}}
+        {{with .Network}}
+        <table>
+            <tr>
+                <th rowspan="2">Точка КЗ</th><th rowspan="2">U, кВ</th>
+                <th colspan="5">Нормальний режим</th><th colspan="5">Мінімальний режим</th>
+            </tr>
+            <tr>
+                <th>R, Ом</th><th>X, Ом</th><th>Z, Ом</th><th>I<sup>(3)</sup>, А</th><th>I<sup>(2)</sup>, А</th>
+                <th>R, Ом</th><th>X, Ом</th><th>Z, Ом</th><th>I<sup>(3)</sup>, А</th><th>I<sup>(2)</sup>, А</th>
+            </tr>
+            {{range .Faults}}
+            <tr>
+                <td>{{.Name}}</td><td>{{printf "%g" .Kv}}</td>
+                <td>{{printf "%.2f" .Normal.R}}</td><td>{{printf "%.2f" .Normal.X}}</td><td>{{printf "%.2f" .Normal.Z}}</td><td>{{printf "%.0f" .Normal.I3}}</td><td>{{printf "%.0f" .Normal.I2}}</td>
+                <td>{{printf "%.2f" .Min.R}}</td><td>{{printf "%.2f" .Min.X}}</td><td>{{printf "%.2f" .Min.Z}}</td><td>{{printf "%.0f" .Min.I3}}</td><td>{{printf "%.0f" .Min.I2}}</td>
+            </tr>
+            {{end}}
+        </table>
+        <table>
+            <tr>
+                <th rowspan="2">Точка КЗ</th>
+                <th colspan="5">Нормальний режим</th><th colspan="5">Мінімальний режим</th>
+            </tr>
+            <tr>
+                <th>Z<sub>2</sub>, Ом</th><th>Z<sub>0</sub>, Ом</th><th>I<sup>(1)</sup>, А</th><th>I<sup>(1,1)</sup>, А</th><th>3I<sub>0</sub><sup>(1,1)</sup>, А</th>
+                <th>Z<sub>2</sub>, Ом</th><th>Z<sub>0</sub>, Ом</th><th>I<sup>(1)</sup>, А</th><th>I<sup>(1,1)</sup>, А</th><th>3I<sub>0</sub><sup>(1,1)</sup>, А</th>
+            </tr>
+            {{range .Faults}}
+            <tr>
+                <td>{{.Name}}</td>
+                {{template "unbalanced" .Normal}}
+                {{template "unbalanced" .Min}}
+            </tr>
+            {{end}}
+        </table>
+        <table>
+            <tr>
+                <th>Точка КЗ</th><th>T<sub>a</sub>, с</th><th>k<sub>уд</sub></th><th>i<sub>уд</sub>, кА</th>
+                <th>Апарат</th><th>i<sub>дин</sub>, кА</th><th>Динамічна стійкість</th>
+            </tr>
+            {{range .Faults}}
+            <tr>
+                <td>{{.Name}}</td>
+                <td>{{if .Normal.Ta}}{{printf "%.4f" .Normal.Ta}}{{else}}∞{{end}}</td><td>{{printf "%.3f" .Normal.Ksh}}</td><td>{{printf "%.2f" .Normal.Ish}}</td>
+                {{if .Checks}}{{with index .Checks 0}}{{template "dynamic" .}}{{end}}{{else}}<td colspan="3">—</td>{{end}}
+            </tr>
+            {{range $i, $c := .Checks}}{{if $i}}<tr><td colspan="4"></td>{{template "dynamic" $c}}</tr>{{end}}{{end}}
+            {{end}}
+        </table>
+        <p>Ударний струм i<sub>уд</sub> = √2·k<sub>уд</sub>·I<sup>(3)</sup> нормального режиму, k<sub>уд</sub> = 1 + e<sup>−0,01/T<sub>a</sub></sup>, T<sub>a</sub> = X/(ωR). Апарати задаються полем "equipment" точки КЗ.</p>
+        <p>I<sup>(1)</sup> — однофазне КЗ на землю, I<sup>(1,1)</sup> — двофазне КЗ на землю (струм фази та струм у землі). "—" — у точці немає шляху для струмів нульової послідовності (ізольована нейтраль).</p>
+        {{end}}
+        <details>
+            <summary>Каталог апаратів</summary>
+            <table>
+                <tr><th>ID</th><th>Тип</th><th>U<sub>ном</sub>, кВ</th><th>I<sub>ном</sub>, А</th><th>i<sub>дин</sub>, кА</th></tr>
+                {{range .Equipment}}<tr><td>{{.ID}}</td><td>{{.Kind}}</td><td>{{printf "%g" .Unom}}</td><td>{{printf "%g" .Inom}}</td><td>{{printf "%g" .Idyn}}</td></tr>{{end}}
+            </table>
+        </details>
+    </section>
+</body>
+</html>
+{{define "dynamic"}}<td>{{.Equipment.ID}}</td><td>{{printf "%g" .Equipment.Idyn}}</td><td>{{if .OK}}<span class="ok">стійкий</span>{{else}}<span class="error">{{if not .VoltageOK}}U<sub>ном</sub> {{printf "%g" .Equipment.Unom}} кВ &lt; {{printf "%g" .Unet}} кВ{{if not .DynamicOK}}, {{end}}{{end}}{{if not .DynamicOK}}не стійкий{{end}}</span>{{end}}</td>{{end}}
+{{define "relay"}}<td>{{f0 .Pickup}}</td><td>{{f2 .RelayPickup}}</td><td>{{f0 .FaultMin}}</td><td>{{f2 .Sensitivity}}</td><td>{{f1 .Required}}</td><td>{{if .OK}}<span class="ok">чутливий</span>{{else}}<span class="error">не чутливий</span>{{end}}</td>{{end}}
+{{define "unbalanced"}}<td>{{printf "%.2f" (hypot .R2 .X2)}}</td>{{if .Grounded}}<td>{{printf "%.2f" (hypot .R0 .X0)}}</td><td>{{printf "%.0f" .I1}}</td><td>{{printf "%.0f" .I11}}</td><td>{{printf "%.0f" .I11E}}</td>{{else}}<td>—</td><td>—</td><td>{{printf "%.0f" .I11}}</td><td>—</td>{{end}}{{end}}`))
 
-func getISh2(calculatedISh3 float64) string {
-	result := calculatedISh3 * (1.73 / 2)
-	return round(result)
+func parseFloat(val string) float64 {
+	f, _ := strconv.ParseFloat(val, 64)
+	return f
 }
 
-func getIShMin3(calculatedZShMin float64) string {
-	result := (115 * 1000) / (1.73 * calculatedZShMin)
-	return round(result)
+// optionalFloat — nil для порожнього поля, щоб відрізнити «не задано» від нуля.
+func optionalFloat(val string) *float64 {
+	if strings.TrimSpace(val) == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		f = math.NaN() // некоректне значення відхиляє withDefaults
+	}
+	return &f
 }
 
-func getIShMin2(calculatedIShMin3 float64) string {
-	result := calculatedIShMin3 * (1.73 / 2)
-	return round(result)
+func render(w http.ResponseWriter, data PageData) {
+	if data.NetworkJSON == "" {
+		data.NetworkJSON = defaultNetworkJSON
+	}
+	data.Equipment = equipmentCatalog
+	data.Cables = cableCatalog
+	data.Conductors = conductors
+	data.Transformers = transformerCatalog
+	_ = tmpl.Execute(w, data)
 }
 
-func getCoef(unn, uvn float64) string {
-	result := math.Pow(unn, 2) / math.Pow(uvn, 2)
-	return format3(result)
+func rootHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	render(w, sessions.Load(sessionID(r)))
 }
 
-func getRAndXShn(a, calculatedCoef float64) string {
-	result := a * calculatedCoef
-	return format2(result)
+// readTaskInputs переносить у data поля всіх трьох задач: вони в одній
+// формі, тож введене в інших секціях зберігається разом із сесією.
+func readTaskInputs(r *http.Request, data *PageData) {
+	_ = r.ParseForm()
+	data.Current = r.FormValue("current")
+	data.HighVoltage = r.FormValue("high_voltage")
+	data.Time = r.FormValue("time")
+	data.CalculatedLoad = r.FormValue("calculated_load")
+	data.Hours = r.FormValue("hours")
+	data.CableType = r.FormValue("cable_type")
+	data.Conductor = r.FormValue("conductor")
+
+	data.PowerKz = r.FormValue("power_kz")
+	data.Transformer2 = r.FormValue("t2_transformer")
+	data.Ucn = r.FormValue("ucn")
+	data.Uk = r.FormValue("uk")
+	data.Snom2 = r.FormValue("t2_snom")
+
+	data.RSn = r.FormValue("r_sn")
+	data.XSn = r.FormValue("x_sn")
+	data.RSnMin = r.FormValue("r_s_min")
+	data.XSnMin = r.FormValue("x_s_min")
+	data.Transformer3 = r.FormValue("t3_transformer")
+	data.Uvn = r.FormValue("uvn")
+	data.Unn = r.FormValue("unn")
+	data.UkMax = r.FormValue("uk_max")
+	data.Snom3 = r.FormValue("t3_snom")
+	data.LineLength = r.FormValue("line_length")
+	data.LineR0 = r.FormValue("line_r0")
+	data.LineX0 = r.FormValue("line_x0")
+	data.LoadCurrent = r.FormValue("load_current")
+	data.CTRatio = r.FormValue("ct_ratio")
+	data.Kn = r.FormValue("kn")
+	data.Ksz = r.FormValue("ksz")
+	data.Kv = r.FormValue("kv")
+	data.KnCutoff = r.FormValue("kn_cutoff")
 }
 
-func getZShn(RSnMin, calculatedXShMin float64) string {
-	result := math.Sqrt(math.Pow(RSnMin, 2) + math.Pow(calculatedXShMin, 2))
-	return format2(result)
+func (data *PageData) runTask1() {
+	res := calculateTask1(Task1Input{
+		Current:        parseFloat(data.Current),
+		HighVoltage:    parseFloat(data.HighVoltage),
+		Time:           parseFloat(data.Time),
+		CalculatedLoad: parseFloat(data.CalculatedLoad),
+		Hours:          parseFloat(data.Hours),
+		CableType:      data.CableType,
+		Conductor:      data.Conductor,
+	})
+	data.Task1 = &res
 }
 
-func getIShn3(calculatedZShn float64) string {
-	result := (11 * 1000) / (1.73 * calculatedZShn)
-	return round(result)
+func (data *PageData) runTask2() {
+	data.Task2, data.Task2Error = nil, ""
+	res, err := calculateTask2(Task2Input{
+		PowerKz:     parseFloat(data.PowerKz),
+		Transformer: data.Transformer2,
+		Ucn:         parseFloat(data.Ucn),
+		Uk:          parseFloat(data.Uk),
+		Snom:        parseFloat(data.Snom2),
+	})
+	if err != nil {
+		data.Task2Error = err.Error()
+		return
+	}
+	data.Task2 = &res
 }
 
-func getResistance(length, a float64) string {
-	result := length * a
-	return format2(result)
+func (data *PageData) runTask3() {
+	data.Task3, data.Task3Error = nil, ""
+	data.Relay, data.RelayError = nil, ""
+	res, err := calculateTask3(Task3Input{
+		RSn:         optionalFloat(data.RSn),
+		XSn:         optionalFloat(data.XSn),
+		RSnMin:      optionalFloat(data.RSnMin),
+		XSnMin:      optionalFloat(data.XSnMin),
+		Transformer: data.Transformer3,
+		Uvn:         parseFloat(data.Uvn),
+		Unn:         parseFloat(data.Unn),
+		UkMax:       parseFloat(data.UkMax),
+		Snom:        parseFloat(data.Snom3),
+		Length:      parseFloat(data.LineLength),
+		R0:          parseFloat(data.LineR0),
+		X0:          parseFloat(data.LineX0),
+	})
+	if err != nil {
+		data.Task3Error = err.Error()
+		return
+	}
+	data.Task3 = &res
+
+	if data.LoadCurrent != "" {
+		relay, err := calculateRelay(RelayInput{
+			LoadCurrent: parseFloat(data.LoadCurrent),
+			CTRatio:     parseFloat(data.CTRatio),
+			Kn:          parseFloat(data.Kn),
+			Ksz:         parseFloat(data.Ksz),
+			Kv:          parseFloat(data.Kv),
+			KnCutoff:    parseFloat(data.KnCutoff),
+		}, res)
+		if err != nil {
+			data.RelayError = err.Error()
+		} else {
+			data.Relay = &relay
+		}
+	}
 }
 
-func render(w http.ResponseWriter, data PageData) {
-	_ = tmpl.Execute(w, data)
+// taskHandler зчитує форму задач, виконує run для сесійного стану,
+// зберігає його та показує сторінку.
+func taskHandler(run func(*PageData)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+		id := ensureSession(w, r)
+		data := sessions.Load(id)
+		readTaskInputs(r, &data)
+		run(&data)
+		sessions.Save(id, data)
+		render(w, data)
+	}
 }
 
-func rootHandler(w http.ResponseWriter, r *http.Request) {
-	render(w, PageData{})
+func computeAll(data *PageData) {
+	data.runTask1()
+	data.runTask2()
+	data.runTask3()
 }
 
-func task1Handler(w http.ResponseWriter, r *http.Request) {
-	_ = r.ParseForm()
-	currentStr := r.FormValue("current")
-	highVoltageStr := r.FormValue("high_voltage")
-	timeStr := r.FormValue("time")
-	calculatedLoadStr := r.FormValue("calculated_load")
-	hoursStr := r.FormValue("hours")
-
-	current := parseFloat(currentStr)
-	highVoltage := parseFloat(highVoltageStr)
-	timeVal := parseFloat(timeStr)
-	calculatedLoad := parseFloat(calculatedLoadStr)
-	hours := parseFloat(hoursStr)
-
-	j := determineJ(hours)
-	calculatedCurrentNormal := getCalculatedCurrentNormal(calculatedLoad, highVoltage)
-	calculatedCurrentPostAccident := getCalculatedCurrentPostAccident(parseFloat(calculatedCurrentNormal))
-	calculatedEconomicCrossSection := getEconomicCrossSection(parseFloat(calculatedCurrentNormal), j)
-	calculatedThermalStability := getThermalStability(current, timeVal)
-
-	render(w, PageData{
-		Current:                    currentStr,
-		HighVoltage:                highVoltageStr,
-		Time:                       timeStr,
-		CalculatedLoad:             calculatedLoadStr,
-		Hours:                      hoursStr,
-		ResultCurrentNormal:        calculatedCurrentNormal,
-		ResultCurrentPostAccident:  calculatedCurrentPostAccident,
-		ResultEconomicCrossSection: calculatedEconomicCrossSection,
-		ResultThermalStability:     calculatedThermalStability,
-	})
+// apiTaskHandler — JSON-варіант задачі для API-клієнтів: вхідні дані в
+// тілі запиту (незадані поля беруться за замовчуванням), результат у відповіді.
+func apiTaskHandler[In, Out any](calculate func(In) (Out, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		var in In
+		dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&in)
+		var out Out
+		if err == nil {
+			out, err = calculate(in)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
 }
 
-func task2Handler(w http.ResponseWriter, r *http.Request) {
-	_ = r.ParseForm()
-	powerKzStr := r.FormValue("power_kz")
-	powerKz := parseFloat(powerKzStr)
-
-	averageNominalPointVoltage := 10.5
-	shortCircuitVoltage := 10.5
-	ratedPowerTransformer := 6.3
-
-	calculatedXs := getXs(averageNominalPointVoltage, powerKz)
-	calculatedXt := getXt(averageNominalPointVoltage, shortCircuitVoltage, ratedPowerTransformer)
-	calculatedTotalResistance := getTotalResistance(parseFloat(calculatedXs), parseFloat(calculatedXt))
-	calculatedInitialCurrentValues := getInitialCurrentValues(averageNominalPointVoltage, calculatedTotalResistance)
-
-	render(w, PageData{
-		PowerKz:              powerKzStr,
-		Xs:                   calculatedXs,
-		Xt:                   calculatedXt,
-		TotalResistance:      strconv.FormatFloat(calculatedTotalResistance, 'f', -1, 64),
-		InitialCurrentValues: calculatedInitialCurrentValues,
-	})
+// writeJSON кодує відповідь до запису заголовків, тож помилка кодування
+// (наприклад, ±Inf чи NaN у результаті) повертається як 500, а не як
+// порожня відповідь 200.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	body, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(map[string]string{"error": "не вдалося сформувати відповідь: " + err.Error()})
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
 }
 
-func task3Handler(w http.ResponseWriter, r *http.Request) {
-	_ = r.ParseForm()
-	rSnStr := r.FormValue("r_sn")
-	xSnStr := r.FormValue("x_sn")
-	rSnMinStr := r.FormValue("r_s_min")
-	xSnMinStr := r.FormValue("x_s_min")
-
-	RSn := parseFloat(rSnStr)
-	XSn := parseFloat(xSnStr)
-	RSnMin := parseFloat(rSnMinStr)
-	XSnMin := parseFloat(xSnMinStr)
-
-	ukMax := 11.1
-	unn := 11.0
-	uvn := 115.0
-	length := 12.37
-
-	calculatedReactance := getReactance(ukMax)
-	calculatedXSh := getXSh(XSn, parseFloat(calculatedReactance))
-	calculatedZSh := getZSh(calculatedXSh)
-	calculatedXShMin := getXShMin(XSnMin, parseFloat(calculatedReactance))
-	calculatedZShMin := getZShMin(RSnMin, calculatedXShMin)
-	calculatedISh3 := getISh3(parseFloat(calculatedZSh))
-	calculatedISh2 := getISh2(parseFloat(calculatedISh3))
-	calculatedIShMin3 := getIShMin3(parseFloat(calculatedZShMin))
-	calculatedIShMin2 := getIShMin2(parseFloat(calculatedIShMin3))
-	calculatedCoef := getCoef(unn, uvn)
-	calculatedRShn := getRAndXShn(RSn, parseFloat(calculatedCoef))
-	calculatedXShn := getRAndXShn(calculatedXSh, parseFloat(calculatedCoef))
-	calculatedZShn := getZShn(parseFloat(calculatedRShn), parseFloat(calculatedXShn))
-	calculatedRShnMin := getRAndXShn(RSnMin, parseFloat(calculatedCoef))
-	calculatedXShnMin := getRAndXShn(calculatedXShMin, parseFloat(calculatedCoef))
-	calculatedZShnMin := getZShMin(parseFloat(calculatedRShnMin), parseFloat(calculatedXShnMin))
-	calculatedIShn3 := getIShn3(parseFloat(calculatedZShn))
-	calculatedIShn2 := getIShMin2(parseFloat(calculatedIShn3))
-	calculatedIShn3Min := getIShn3(parseFloat(calculatedZShnMin))
-	calculatedIShn2Min := getIShMin2(parseFloat(calculatedIShn3Min))
-	calculateResistance := getResistance(length, 0.64)
-	calculateReactanceX := getResistance(length, 0.363)
-	calculateRSumN := getXSh(parseFloat(calculateResistance), parseFloat(calculatedRShn))
-	calculateXSumN := math.Round(getXSh(parseFloat(calculateReactanceX), parseFloat(calculatedXShn))*10) / 10
-	calculatedZSumN := getZShn(calculateRSumN, calculateXSumN)
-	calculateRSumNMin := getXSh(parseFloat(calculateResistance), parseFloat(calculatedRShnMin))
-	calculateXSumNMin := getXSh(parseFloat(calculateReactanceX), parseFloat(calculatedXShnMin))
-	calculatedZSumNMin := getZShn(calculateRSumNMin, calculateXSumNMin)
-	calculatedIln3 := getIShn3(parseFloat(calculatedZSumN))
-	calculatedIln2 := getIShMin2(parseFloat(calculatedIln3))
-	calculatedIlnMin3 := getIShn3(parseFloat(calculatedZSumNMin))
-	calculatedIlnMin2 := getIShMin2(parseFloat(calculatedIlnMin3))
-
-	render(w, PageData{
-		RSn:      rSnStr,
-		XSn:      xSnStr,
-		RSnMin:   rSnMinStr,
-		XSnMin:   xSnMinStr,
-		ISh3:     calculatedISh3,
-		ISh2:     calculatedISh2,
-		IShMin3:  calculatedIShMin3,
-		IShMin2:  calculatedIShMin2,
-		IShn3:    calculatedIShn3,
-		IShn2:    calculatedIShn2,
-		IShnMin3: calculatedIShn3Min,
-		IShnMin2: calculatedIShn2Min,
-		Iln3:     calculatedIln3,
-		Iln2:     calculatedIln2,
-		IlnMin3:  calculatedIlnMin3,
-		IlnMin2:  calculatedIlnMin2,
-	})
+func resetHandler(w http.ResponseWriter, r *http.Request) {
+	if id := sessionID(r); id != "" {
+		sessions.Delete(id)
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
 func main() {
 	http.HandleFunc("/", rootHandler)
-	http.HandleFunc("/task1", task1Handler)
-	http.HandleFunc("/task2", task2Handler)
-	http.HandleFunc("/task3", task3Handler)
+	http.HandleFunc("/task1", taskHandler((*PageData).runTask1))
+	http.HandleFunc("/task2", taskHandler((*PageData).runTask2))
+	http.HandleFunc("/task3", taskHandler((*PageData).runTask3))
+	http.HandleFunc("/all", taskHandler(computeAll))
+	http.HandleFunc("/reset", resetHandler)
+	http.HandleFunc("/network", networkHandler)
+	http.HandleFunc("/network/svg", networkSVGHandler)
+	http.HandleFunc("/api/network", apiNetworkHandler)
+	http.HandleFunc("/api/task2", apiTaskHandler(calculateTask2))
+	http.HandleFunc("/api/task3", apiTaskHandler(calculateTask3))
 
 	fmt.Println("Server started at http://localhost:8080")
 	_ = http.ListenAndServe(":8080", nil)
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPITask2(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"powerKz": 200}`, http.StatusOK},
+		{`{}`, http.StatusBadRequest}, // Sк = 0 дає Xс = +Inf
+		{`{"powerKz": -200}`, http.StatusBadRequest},
+		{`{"powerKz": 200, "transformer": "немає"}`, http.StatusBadRequest},
+		{`{"powerKz": 200, "extra": 1}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		apiTaskHandler(calculateTask2)(rec, httptest.NewRequest(http.MethodPost, "/api/task2", strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s: статус %d, want %d", tt.body, rec.Code, tt.status)
+		}
+		var out map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Errorf("%s: відповідь не JSON: %q", tt.body, rec.Body.String())
+		}
+	}
+}
+
+func TestWriteJSONEncodeError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeJSON(rec, http.StatusOK, map[string]float64{"xs": math.Inf(1)})
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("статус %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("відповідь без помилки: %q", rec.Body.String())
+	}
+}
+
+// /api/task3 віддає camelCase-ключі, як /api/task2.
+func TestAPITask3Keys(t *testing.T) {
+	rec := httptest.NewRecorder()
+	apiTaskHandler(calculateTask3)(rec, httptest.NewRequest(http.MethodPost, "/api/task3", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("статус %d: %s", rec.Code, rec.Body.String())
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"input", "reactance", "xSh", "iSh3", "coef", "iShn3", "rL", "zSumN", "iLnMin2"} {
+		if _, ok := out[key]; !ok {
+			t.Errorf("немає ключа %q", key)
+		}
+	}
+	var in map[string]any
+	if err := json.Unmarshal(out["input"], &in); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := in["rSn"]; !ok {
+		t.Errorf("input без ключа rSn: %s", out["input"])
+	}
+}
+
+func TestAPITask3SystemImpedance(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"rSn": 0}`, http.StatusOK},
+		{`{"rSn": null}`, http.StatusOK},
+		{`{"rSn": -1}`, http.StatusBadRequest},
+		{`{"xSnMin": -65.68}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		apiTaskHandler(calculateTask3)(rec, httptest.NewRequest(http.MethodPost, "/api/task3", strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s: статус %d, want %d: %s", tt.body, rec.Code, tt.status, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,613 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"math/cmplx"
+	"net/http"
+)
+
+// Загальна модель мережі для розрахунку струмів КЗ: вузли (шини) з
+// напругою, елементи (система, трансформатори, лінії) між ними та точки КЗ.
+// Опори зводяться у відносні одиниці, еквівалентний опір у точці КЗ
+// визначається з вузлової матриці провідностей, тому допускаються і
+// паралельні елементи, і кільцеві схеми.
+
+type Bus struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name"`
+	Kv   float64 `json:"kv"` // середня номінальна напруга, кВ
+}
+
+type Element struct {
+	Type string `json:"type"` // source | transformer | line
+	Name string `json:"name"`
+	From string `json:"from,omitempty"` // для source не задається — система підключена між землею та To
+	To   string `json:"to"`
+
+	// source: опір системи в нормальному та мінімальному режимах, Ом,
+	// або потужність КЗ системи Sк, МВ·А (тоді X = U²/Sк, R = 0)
+	R     float64 `json:"r,omitempty"`
+	X     float64 `json:"x,omitempty"`
+	RMin  float64 `json:"rMin,omitempty"`
+	XMin  float64 `json:"xMin,omitempty"`
+	Sk    float64 `json:"sk,omitempty"`
+	SkMin float64 `json:"skMin,omitempty"`
+
+	// transformer: Sном.т, МВ·А; uк, %; ΔPк, кВт (необов'язково).
+	// Опір зводиться до напруги вузла From.
+	Snom float64 `json:"snom,omitempty"`
+	Uk   float64 `json:"uk,omitempty"`
+	Pk   float64 `json:"pk,omitempty"`
+
+	// line: довжина, км; питомі опори r0, x0, Ом/км
+	Length float64 `json:"length,omitempty"`
+	R0     float64 `json:"r0,omitempty"`
+	X0     float64 `json:"x0,omitempty"`
+
+	// Опори зворотної (r2, x2) та нульової (rZero, xZero) послідовностей,
+	// Ом (для ліній — Ом/км). Якщо не задані: z2 = z1; z0 = z1 для системи
+	// та трансформатора, r0 + 0,15 та 3,5·x0 для лінії (ПЛ без троса).
+	// r2Min … xZeroMin — те саме для системи в мінімальному режимі; якщо
+	// не задані, z2 та z0 мінімального режиму визначаються через rMin,
+	// xMin, skMin, як і z1.
+	R2       float64 `json:"r2,omitempty"`
+	X2       float64 `json:"x2,omitempty"`
+	RZero    float64 `json:"rZero,omitempty"`
+	XZero    float64 `json:"xZero,omitempty"`
+	R2Min    float64 `json:"r2Min,omitempty"`
+	X2Min    float64 `json:"x2Min,omitempty"`
+	RZeroMin float64 `json:"rZeroMin,omitempty"`
+	XZeroMin float64 `json:"xZeroMin,omitempty"`
+
+	// Connection — схема з'єднання обмоток трансформатора, перша обмотка
+	// з боку From: YNd, Dyn, YNyn, Yd, Yyn… За замовчуванням YNd.
+	Connection string `json:"connection,omitempty"`
+
+	// OffInMin — елемент вимкнений у мінімальному режимі
+	OffInMin bool `json:"offInMin,omitempty"`
+}
+
+type FaultPoint struct {
+	Bus  string `json:"bus"`
+	Name string `json:"name"`
+
+	// Equipment — ID апаратів з equipmentCatalog, встановлених у точці,
+	// для перевірки на динамічну стійкість
+	Equipment []string `json:"equipment,omitempty"`
+}
+
+type Network struct {
+	BaseMVA  float64      `json:"baseMVA,omitempty"` // базова потужність, за замовчуванням 100 МВ·А
+	Buses    []Bus        `json:"buses"`
+	Elements []Element    `json:"elements"`
+	Faults   []FaultPoint `json:"faults"`
+}
+
+type ModeResult struct {
+	R  float64 `json:"r"`  // Ом, зведений до напруги точки КЗ
+	X  float64 `json:"x"`  // Ом
+	Z  float64 `json:"z"`  // Ом
+	I3 float64 `json:"i3"` // А, трифазне КЗ
+	I2 float64 `json:"i2"` // А, двофазне КЗ
+
+	// Опори зворотної та нульової послідовностей, Ом.
+	// Grounded = false — у точці немає шляху для струмів нульової
+	// послідовності (ізольована нейтраль), тоді I1 = 0, а I11 = I2.
+	R2       float64 `json:"r2"`
+	X2       float64 `json:"x2"`
+	R0       float64 `json:"r0"`
+	X0       float64 `json:"x0"`
+	Grounded bool    `json:"grounded"`
+	I1       float64 `json:"i1"`   // А, однофазне КЗ на землю
+	I11      float64 `json:"i11"`  // А, струм пошкоджених фаз при двофазному КЗ на землю
+	I11E     float64 `json:"i11e"` // А, струм у землі 3·I0 при двофазному КЗ на землю
+
+	Ta  float64 `json:"ta"`  // с, стала часу аперіодичної складової (0 — якщо R = 0)
+	Ksh float64 `json:"ksh"` // ударний коефіцієнт
+	Ish float64 `json:"ish"` // кА, ударний струм трифазного КЗ
+}
+
+type FaultResult struct {
+	Name   string     `json:"name"`
+	Bus    string     `json:"bus"`
+	Kv     float64    `json:"kv"`
+	Normal ModeResult `json:"normal"`
+	Min    ModeResult `json:"min"`
+
+	// Перевірка апаратів за ударним струмом нормального режиму
+	Checks []DynamicCheck `json:"checks,omitempty"`
+}
+
+type NetworkReport struct {
+	Faults []FaultResult `json:"faults"`
+}
+
+// Мережа ХПнЕМ із task3 — приклад, яким заповнюється форма.
+const defaultNetworkJSON = `{
+  "buses": [
+    {"id": "HV", "name": "Шини 110 кВ", "kv": 115},
+    {"id": "K1", "name": "Шини 10 кВ", "kv": 11},
+    {"id": "K2", "name": "Кінець лінії 10 кВ", "kv": 11}
+  ],
+  "elements": [
+    {"type": "source", "name": "Система", "to": "HV", "r": 10.65, "x": 24.02, "rMin": 34.88, "xMin": 65.68},
+    {"type": "transformer", "name": "Т1", "from": "HV", "to": "K1", "snom": 6.3, "uk": 11.1},
+    {"type": "line", "name": "Л1", "from": "K1", "to": "K2", "length": 12.37, "r0": 0.64, "x0": 0.363}
+  ],
+  "faults": [
+    {"bus": "K1", "name": "К1", "equipment": ["BB/TEL-10-20/1000", "КРУ К-59"]},
+    {"bus": "K2", "name": "К2"}
+  ]
+}`
+
+func parseNetwork(data []byte) (Network, error) {
+	var n Network
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&n); err != nil {
+		return n, fmt.Errorf("некоректний JSON мережі: %w", err)
+	}
+	return n, n.validate()
+}
+
+func (n *Network) validate() error {
+	if n.BaseMVA <= 0 {
+		n.BaseMVA = 100
+	}
+	if len(n.Buses) == 0 {
+		return errors.New("не задано жодного вузла")
+	}
+	buses := map[string]bool{}
+	for _, b := range n.Buses {
+		if b.ID == "" {
+			return errors.New("вузол без id")
+		}
+		if buses[b.ID] {
+			return fmt.Errorf("вузол %q задано двічі", b.ID)
+		}
+		if b.Kv <= 0 {
+			return fmt.Errorf("вузол %q: напруга має бути більшою за 0", b.ID)
+		}
+		buses[b.ID] = true
+	}
+
+	sources := 0
+	for i, e := range n.Elements {
+		name := e.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if !buses[e.To] {
+			return fmt.Errorf("елемент %s: невідомий вузол %q", name, e.To)
+		}
+		if e.Type != "source" && !buses[e.From] {
+			return fmt.Errorf("елемент %s: невідомий вузол %q", name, e.From)
+		}
+		switch e.Type {
+		case "source":
+			sources++
+			if e.Sk <= 0 && e.R == 0 && e.X == 0 {
+				return fmt.Errorf("система %s: задайте r, x або sk", name)
+			}
+		case "transformer":
+			if e.Snom <= 0 || e.Uk <= 0 {
+				return fmt.Errorf("трансформатор %s: snom та uk мають бути більшими за 0", name)
+			}
+		case "line":
+			if e.Length <= 0 || (e.R0 == 0 && e.X0 == 0) {
+				return fmt.Errorf("лінія %s: задайте length, r0, x0", name)
+			}
+		default:
+			return fmt.Errorf("елемент %s: невідомий тип %q", name, e.Type)
+		}
+	}
+	if sources == 0 {
+		return errors.New("у мережі немає джерела (type: source)")
+	}
+
+	if len(n.Faults) == 0 {
+		return errors.New("не задано жодної точки КЗ")
+	}
+	for i, f := range n.Faults {
+		if !buses[f.Bus] {
+			return fmt.Errorf("точка КЗ: невідомий вузол %q", f.Bus)
+		}
+		if f.Name == "" {
+			n.Faults[i].Name = f.Bus
+		}
+		for _, id := range f.Equipment {
+			if _, ok := findEquipment(id); !ok {
+				return fmt.Errorf("точка %s: апарат %q відсутній у каталозі", n.Faults[i].Name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// branch — гілка заступної схеми у відносних одиницях; to < 0 — земля.
+type branch struct {
+	from, to int
+	z        complex128
+}
+
+func (n Network) busIndex() map[string]int {
+	idx := make(map[string]int, len(n.Buses))
+	for i, b := range n.Buses {
+		idx[b.ID] = i
+	}
+	return idx
+}
+
+// toPU переводить опір в омах при напрузі kv у відносні одиниці.
+func (n Network) toPU(z complex128, kv float64) complex128 {
+	return z * complex(n.BaseMVA/(kv*kv), 0)
+}
+
+// Послідовності симетричних складових.
+const (
+	seqPositive = iota
+	seqNegative
+	seqZero
+)
+
+func pick(v, fallback float64) float64 {
+	if v != 0 {
+		return v
+	}
+	return fallback
+}
+
+// elementZ — опір елемента в омах для заданої послідовності, зведений до
+// напруги вузла, до якого він прив'язаний (To для системи, From для решти).
+func elementZ(e Element, kv float64, seq int, minMode bool) complex128 {
+	switch e.Type {
+	case "source":
+		// У мінімальному режимі всі послідовності беруться з параметрів
+		// мінімального режиму, а нормальні — лише якщо таких немає.
+		r, x, sk := e.R, e.X, e.Sk
+		r2, x2, rZero, xZero := e.R2, e.X2, e.RZero, e.XZero
+		if minMode {
+			if e.RMin != 0 || e.XMin != 0 || e.SkMin > 0 {
+				r, x, sk = e.RMin, e.XMin, e.SkMin
+				r2, x2, rZero, xZero = e.R2Min, e.X2Min, e.RZeroMin, e.XZeroMin
+			} else {
+				if e.R2Min != 0 || e.X2Min != 0 {
+					r2, x2 = e.R2Min, e.X2Min
+				}
+				if e.RZeroMin != 0 || e.XZeroMin != 0 {
+					rZero, xZero = e.RZeroMin, e.XZeroMin
+				}
+			}
+		}
+		if r == 0 && x == 0 && sk > 0 {
+			x = kv * kv / sk
+		}
+		switch {
+		case seqNegative == seq && (r2 != 0 || x2 != 0):
+			r, x = r2, x2
+		case seqZero == seq && (rZero != 0 || xZero != 0):
+			r, x = rZero, xZero
+		}
+		return complex(r, x)
+	case "transformer":
+		x := (e.Uk / 100) * kv * kv / e.Snom
+		r := (e.Pk / 1000) * kv * kv / (e.Snom * e.Snom)
+		if seq == seqZero && (e.RZero != 0 || e.XZero != 0) {
+			r, x = e.RZero, e.XZero
+		}
+		return complex(r, x)
+	case "line":
+		r, x := e.R0, e.X0
+		switch seq {
+		case seqNegative:
+			r, x = pick(e.R2, r), pick(e.X2, x)
+		case seqZero:
+			r, x = pick(e.RZero, r+0.15), pick(e.XZero, 3.5*x)
+		}
+		return complex(r*e.Length, x*e.Length)
+	}
+	return 0
+}
+
+// windings розбирає схему з'єднання трансформатора: чи заземлена
+// нейтраль зірки з боку From та з боку To, чи є трикутник з кожного боку.
+func windings(connection string) (fromGrounded, fromDelta, toGrounded, toDelta bool) {
+	c := connection
+	if c == "" {
+		c = "YNd"
+	}
+	// перша обмотка — великими літерами, друга — малими (група — цифри)
+	i := 1
+	for i < len(c) && c[i] >= 'A' && c[i] <= 'Z' {
+		i++
+	}
+	first, second := c[:i], c[i:]
+	for len(second) > 0 && second[len(second)-1] >= '0' && second[len(second)-1] <= '9' {
+		second = second[:len(second)-1]
+	}
+	return first == "YN" || first == "ZN", first == "D",
+		second == "yn" || second == "zn", second == "d"
+}
+
+func (n Network) branches(seq int, minMode bool) []branch {
+	idx := n.busIndex()
+	var list []branch
+	for _, e := range n.Elements {
+		if minMode && e.OffInMin {
+			continue
+		}
+		if e.Type == "source" {
+			to := idx[e.To]
+			kv := n.Buses[to].Kv
+			list = append(list, branch{from: to, to: -1, z: n.toPU(elementZ(e, kv, seq, minMode), kv)})
+			continue
+		}
+		from, to := idx[e.From], idx[e.To]
+		kv := n.Buses[from].Kv
+		z := n.toPU(elementZ(e, kv, seq, minMode), kv)
+
+		if e.Type == "transformer" && seq == seqZero {
+			// Нульова послідовність проходить через трансформатор лише за
+			// наявності заземленої зірки; трикутник замикає її на землю.
+			fromGrounded, fromDelta, toGrounded, toDelta := windings(e.Connection)
+			switch {
+			case fromGrounded && toGrounded:
+				list = append(list, branch{from: from, to: to, z: z})
+			case fromGrounded && toDelta:
+				list = append(list, branch{from: from, to: -1, z: z})
+			case toGrounded && fromDelta:
+				list = append(list, branch{from: to, to: -1, z: z})
+			}
+			continue
+		}
+		list = append(list, branch{from: from, to: to, z: z})
+	}
+	return list
+}
+
+var errNotConnected = errors.New("вузол не з'єднаний з джерелом")
+
+// theveninZ — еквівалентний опір схеми відносно вузла k (діагональний
+// елемент матриці вузлових опорів). Вузли, не з'єднані із землею через
+// гілки схеми, відкидаються.
+func theveninZ(nodes int, branches []branch, k int) (complex128, error) {
+	// вузли, досяжні від землі
+	adj := make([][]int, nodes)
+	grounded := make([]bool, nodes)
+	var queue []int
+	for _, b := range branches {
+		if b.z == 0 {
+			continue
+		}
+		if b.to < 0 {
+			if !grounded[b.from] {
+				grounded[b.from] = true
+				queue = append(queue, b.from)
+			}
+			continue
+		}
+		adj[b.from] = append(adj[b.from], b.to)
+		adj[b.to] = append(adj[b.to], b.from)
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range adj[v] {
+			if !grounded[u] {
+				grounded[u] = true
+				queue = append(queue, u)
+			}
+		}
+	}
+	if !grounded[k] {
+		return 0, errNotConnected
+	}
+
+	pos := make([]int, nodes)
+	size := 0
+	for i := range pos {
+		pos[i] = -1
+		if grounded[i] {
+			pos[i] = size
+			size++
+		}
+	}
+
+	y := make([][]complex128, size)
+	for i := range y {
+		y[i] = make([]complex128, size)
+	}
+	for _, b := range branches {
+		if b.z == 0 {
+			continue
+		}
+		adm := 1 / b.z
+		i := pos[b.from]
+		if i < 0 {
+			continue
+		}
+		y[i][i] += adm
+		if b.to >= 0 {
+			j := pos[b.to]
+			y[j][j] += adm
+			y[i][j] -= adm
+			y[j][i] -= adm
+		}
+	}
+
+	rhs := make([]complex128, size)
+	rhs[pos[k]] = 1
+	v, err := solveComplex(y, rhs)
+	if err != nil {
+		return 0, err
+	}
+	return v[pos[k]], nil
+}
+
+// solveComplex розв'язує A·x = b методом Гауса з вибором головного елемента.
+func solveComplex(a [][]complex128, b []complex128) ([]complex128, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if cmplx.Abs(a[row][col]) > cmplx.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if cmplx.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("вироджена матриця провідностей (перевірте опори елементів)")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for c := col; c < n; c++ {
+				a[row][c] -= f * a[col][c]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+	x := make([]complex128, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for c := row + 1; c < n; c++ {
+			sum -= a[row][c] * x[c]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, nil
+}
+
+func (n Network) solveMode(bus int, minMode bool) (ModeResult, error) {
+	kv := n.Buses[bus].Kv
+	toOhm := complex(kv*kv/n.BaseMVA, 0)
+
+	z1pu, err := theveninZ(len(n.Buses), n.branches(seqPositive, minMode), bus)
+	if err != nil {
+		return ModeResult{}, err
+	}
+	z2pu, err := theveninZ(len(n.Buses), n.branches(seqNegative, minMode), bus)
+	if err != nil {
+		return ModeResult{}, err
+	}
+	z1, z2 := z1pu*toOhm, z2pu*toOhm
+
+	res := ModeResult{R: real(z1), X: imag(z1), Z: cmplx.Abs(z1), R2: real(z2), X2: imag(z2)}
+	e := complex(kv*1000/math.Sqrt(3), 0) // фазна ЕРС, В
+
+	res.I3 = cmplx.Abs(e / z1)
+	res.Ta, res.Ksh = shockFactor(res.R, res.X)
+	res.Ish = shockCurrent(res.Ksh, res.I3)
+	res.I2 = math.Sqrt(3) * cmplx.Abs(e/(z1+z2))
+	res.I11 = res.I2
+
+	z0pu, err := theveninZ(len(n.Buses), n.branches(seqZero, minMode), bus)
+	if errors.Is(err, errNotConnected) {
+		return res, nil
+	}
+	if err != nil {
+		return ModeResult{}, err
+	}
+	z0 := z0pu * toOhm
+	res.Grounded = true
+	res.R0, res.X0 = real(z0), imag(z0)
+
+	// однофазне КЗ: I = 3·E / (Z1 + Z2 + Z0)
+	res.I1 = cmplx.Abs(3 * e / (z1 + z2 + z0))
+
+	// двофазне КЗ на землю: Z2 і Z0 паралельно
+	i1 := e / (z1 + z2*z0/(z2+z0))
+	i2 := -i1 * z0 / (z2 + z0)
+	i0 := -i1 * z2 / (z2 + z0)
+	a := cmplx.Rect(1, 2*math.Pi/3)
+	ib := i0 + a*a*i1 + a*i2
+	ic := i0 + a*i1 + a*a*i2
+	res.I11 = math.Max(cmplx.Abs(ib), cmplx.Abs(ic))
+	res.I11E = cmplx.Abs(3 * i0)
+	return res, nil
+}
+
+func (n Network) Solve() (NetworkReport, error) {
+	idx := n.busIndex()
+	var report NetworkReport
+	for _, f := range n.Faults {
+		bus := idx[f.Bus]
+		fr := FaultResult{Name: f.Name, Bus: f.Bus, Kv: n.Buses[bus].Kv}
+
+		var err error
+		if fr.Normal, err = n.solveMode(bus, false); err != nil {
+			return report, fmt.Errorf("точка %s, нормальний режим: %w", f.Name, err)
+		}
+		if fr.Min, err = n.solveMode(bus, true); err != nil {
+			return report, fmt.Errorf("точка %s, мінімальний режим: %w", f.Name, err)
+		}
+		if fr.Checks, err = checkDynamic(f.Equipment, fr.Kv, fr.Normal.Ish); err != nil {
+			return report, err
+		}
+		report.Faults = append(report.Faults, fr)
+	}
+	return report, nil
+}
+
+// ----- обробники -----
+
+func networkHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	_ = r.ParseForm()
+	networkJSON := r.FormValue("network")
+	id := ensureSession(w, r)
+	data := sessions.Load(id)
+	data.NetworkJSON = networkJSON
+	data.Network, data.NetworkError, data.NetworkSVG = nil, "", ""
+
+	n, err := parseNetwork([]byte(networkJSON))
+	if err == nil {
+		var report NetworkReport
+		report, err = n.Solve()
+		data.Network = &report
+		if err != nil {
+			data.NetworkSVG = template.HTML(n.Diagram(nil))
+		} else {
+			data.NetworkSVG = template.HTML(n.Diagram(&report))
+		}
+	}
+	if err != nil {
+		data.Network = nil
+		data.NetworkError = err.Error()
+	}
+	sessions.Save(id, data)
+	render(w, data)
+}
+
+// apiNetworkHandler — той самий розрахунок для API-клієнтів: JSON мережі
+// в тілі запиту, JSON з результатами у відповіді.
+func apiNetworkHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	var n Network
+	if err == nil {
+		n, err = parseNetwork(raw)
+	}
+	var report NetworkReport
+	if err == nil {
+		report, err = n.Solve()
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
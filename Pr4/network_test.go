@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestElementZSourceSequences(t *testing.T) {
+	// Система 110 кВ: z1 = j10 / j20 Ом, z2 нормального режиму j11 Ом.
+	src := Element{Type: "source", To: "A", X: 10, XMin: 20, X2: 11, XZero: 15}
+	tests := []struct {
+		name    string
+		e       Element
+		seq     int
+		minMode bool
+		want    complex128
+	}{
+		{"z1 нормальний", src, seqPositive, false, complex(0, 10)},
+		{"z1 мінімальний", src, seqPositive, true, complex(0, 20)},
+		{"z2 нормальний", src, seqNegative, false, complex(0, 11)},
+		// z2Min не задано — z2 = z1 мінімального режиму, а не z2 нормального
+		{"z2 мінімальний без x2Min", src, seqNegative, true, complex(0, 20)},
+		{"z0 мінімальний без xZeroMin", src, seqZero, true, complex(0, 20)},
+		{"z2 мінімальний з x2Min", func() Element { e := src; e.X2Min = 22; return e }(), seqNegative, true, complex(0, 22)},
+		// параметрів мінімального режиму немає — він збігається з нормальним
+		{"z2 мінімальний без rMin/xMin", Element{Type: "source", To: "A", X: 10, X2: 11}, seqNegative, true, complex(0, 11)},
+		{"z1 через Sк", Element{Type: "source", To: "A", Sk: 1210}, seqPositive, false, complex(0, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elementZ(tt.e, 110, tt.seq, tt.minMode); got != tt.want {
+				t.Errorf("elementZ = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
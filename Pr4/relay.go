@@ -0,0 +1,84 @@
+package main
+
+import "errors"
+
+// Уставки максимального струмового захисту (МСЗ) та струмової відсічки
+// фідера 10 кВ за струмами КЗ task3: максимальні — Iл.н(3) в кінці лінії,
+// мінімальні — IШ.н.min(2) на шинах та Iл.н.min(2) в кінці лінії.
+
+// Мінімально допустимі коефіцієнти чутливості.
+const (
+	minSensitivityOvercurrent = 1.5 // МСЗ, основна зона — кінець лінії
+	minSensitivityCutoff      = 2.0 // відсічка, місце встановлення захисту
+)
+
+type RelayInput struct {
+	LoadCurrent float64 // Iроб.max, А
+	CTRatio     float64 // коефіцієнт трансформації ТС, nт
+	Kn          float64 // коефіцієнт надійності МСЗ
+	Ksz         float64 // коефіцієнт самозапуску
+	Kv          float64 // коефіцієнт повернення реле
+	KnCutoff    float64 // коефіцієнт надійності відсічки
+}
+
+// withDefaults підставляє типові значення коефіцієнтів, не заданих у формі.
+func (in RelayInput) withDefaults() RelayInput {
+	if in.CTRatio <= 0 {
+		in.CTRatio = 40 // 200/5
+	}
+	if in.Kn <= 0 {
+		in.Kn = 1.2
+	}
+	if in.Ksz <= 0 {
+		in.Ksz = 1.5
+	}
+	if in.Kv <= 0 {
+		in.Kv = 0.85
+	}
+	if in.KnCutoff <= 0 {
+		in.KnCutoff = 1.3
+	}
+	return in
+}
+
+type RelaySetting struct {
+	Pickup      float64 // Iсз, А первинний
+	RelayPickup float64 // Iср, А вторинний
+	FaultMin    float64 // струм, за яким перевіряється чутливість, А
+	Sensitivity float64 // kч
+	Required    float64 // мінімально допустимий kч
+	OK          bool
+}
+
+type RelayResult struct {
+	Input       RelayInput
+	Overcurrent RelaySetting
+	Cutoff      RelaySetting
+}
+
+func newSetting(pickup, faultMin, required, ctRatio float64) RelaySetting {
+	s := RelaySetting{
+		Pickup:      pickup,
+		RelayPickup: pickup / ctRatio, // схема «повна зірка», kсх = 1
+		FaultMin:    faultMin,
+		Required:    required,
+	}
+	s.Sensitivity = faultMin / pickup
+	s.OK = s.Sensitivity >= required
+	return s
+}
+
+func calculateRelay(in RelayInput, t3 Task3Result) (RelayResult, error) {
+	if in.LoadCurrent <= 0 {
+		return RelayResult{}, errors.New("Вкажіть максимальний робочий струм фідера Iроб.max")
+	}
+	in = in.withDefaults()
+	res := RelayResult{Input: in}
+
+	// МСЗ: Iсз = kн·kсзп/kп · Iроб.max, чутливість — у кінці лінії
+	res.Overcurrent = newSetting(in.Kn*in.Ksz/in.Kv*in.LoadCurrent, t3.IlnMin2, minSensitivityOvercurrent, in.CTRatio)
+
+	// Відсічка: Iсз = kн.в · Iл.н(3), чутливість — на шинах 10 кВ
+	res.Cutoff = newSetting(in.KnCutoff*t3.Iln3, t3.IShnMin2, minSensitivityCutoff, in.CTRatio)
+	return res, nil
+}
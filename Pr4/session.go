@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Стан сторінки (введені дані та результати всіх секцій) зберігається в
+// пам'яті сервера за ідентифікатором сесії з cookie, тому розрахунок однієї
+// задачі не стирає інші. Після перезапуску сервера сесії втрачаються.
+// Сесія створюється лише тоді, коли є що зберегти (POST форми); перегляд
+// сторінки без cookie нічого не додає до сховища, а саме сховище обмежене
+// maxSessions записами — понад ліміт витісняється найдавніша сесія.
+
+const (
+	sessionCookie = "pr4-session"
+	sessionTTL    = 24 * time.Hour
+	maxSessions   = 1000
+)
+
+var sessionIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+type sessionEntry struct {
+	data PageData
+	seen time.Time
+}
+
+type SessionStore struct {
+	mu      sync.Mutex
+	entries map[string]sessionEntry
+}
+
+var sessions = &SessionStore{entries: map[string]sessionEntry{}}
+
+// Load повертає збережений стан або порожній PageData для нової сесії.
+func (s *SessionStore) Load(id string) PageData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || time.Since(e.seen) > sessionTTL {
+		return PageData{}
+	}
+	return e.data
+}
+
+func (s *SessionStore) Save(id string, data PageData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, e := range s.entries {
+		if now.Sub(e.seen) > sessionTTL {
+			delete(s.entries, k)
+		}
+	}
+	if _, ok := s.entries[id]; !ok && len(s.entries) >= maxSessions {
+		oldest, seen := "", now
+		for k, e := range s.entries {
+			if e.seen.Before(seen) {
+				oldest, seen = k, e.seen
+			}
+		}
+		delete(s.entries, oldest)
+	}
+	s.entries[id] = sessionEntry{data: data, seen: now}
+}
+
+func (s *SessionStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func (s *SessionStore) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, id)
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// sessionID повертає ідентифікатор сесії з cookie або "", якщо сесії немає.
+func sessionID(r *http.Request) string {
+	if c, err := r.Cookie(sessionCookie); err == nil && sessionIDPattern.MatchString(c.Value) {
+		return c.Value
+	}
+	return ""
+}
+
+// ensureSession — те саме, але за відсутності cookie створює нову сесію.
+func ensureSession(w http.ResponseWriter, r *http.Request) string {
+	if id := sessionID(r); id != "" {
+		return id
+	}
+	id := newSessionID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	r.AddCookie(&http.Cookie{Name: sessionCookie, Value: id})
+	return id
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Перегляд сторінки без cookie не створює сесію; POST форми — створює.
+func TestSessionCreatedOnPost(t *testing.T) {
+	before := sessions.Len()
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		rootHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /: статус %d", rec.Code)
+		}
+		if c := rec.Result().Cookies(); len(c) != 0 {
+			t.Errorf("GET / встановив cookie %v", c)
+		}
+	}
+	rec := httptest.NewRecorder()
+	taskHandler((*PageData).runTask2)(rec, httptest.NewRequest(http.MethodGet, "/task2", nil))
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("GET /task2: статус %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if got := sessions.Len(); got != before {
+		t.Fatalf("сесій %d після GET, want %d", got, before)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/task2", strings.NewReader("power_kz=200"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	taskHandler((*PageData).runTask2)(rec, req)
+	if len(rec.Result().Cookies()) != 1 {
+		t.Errorf("POST /task2 не встановив cookie сесії")
+	}
+	if got := sessions.Len(); got != before+1 {
+		t.Errorf("сесій %d після POST, want %d", got, before+1)
+	}
+}
+
+func TestSessionStoreCap(t *testing.T) {
+	s := &SessionStore{entries: map[string]sessionEntry{}}
+	for i := 0; i < maxSessions+10; i++ {
+		s.Save(fmt.Sprintf("%032x", i), PageData{PowerKz: fmt.Sprint(i)})
+	}
+	if got := s.Len(); got != maxSessions {
+		t.Errorf("сесій %d, want %d", got, maxSessions)
+	}
+	last := fmt.Sprintf("%032x", maxSessions+9)
+	if got := s.Load(last).PowerKz; got != fmt.Sprint(maxSessions+9) {
+		t.Errorf("остання сесія втрачена: %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+// TransformerType — силовий трансформатор ГПП. UkMax — uк на крайньому
+// відгалуженні РПН, що дає найбільший опір (для розрахунку мінімальних
+// струмів); якщо не задано, береться Uk.
+type TransformerType struct {
+	ID    string  `json:"id"`
+	Snom  float64 `json:"snom"`  // МВ·А
+	Uvn   float64 `json:"uvn"`   // кВ
+	Unn   float64 `json:"unn"`   // кВ
+	Uk    float64 `json:"uk"`    // %
+	UkMax float64 `json:"ukMax"` // %
+	Pk    float64 `json:"pk"`    // кВт
+}
+
+// Трансформатор прикладу task2/task3.
+const defaultTransformer = "ТМН-6300/110"
+
+var transformerCatalog = []TransformerType{
+	{ID: "ТМН-6300/110", Snom: 6.3, Uvn: 115, Unn: 11, Uk: 10.5, UkMax: 11.1, Pk: 44},
+	{ID: "ТДН-10000/110", Snom: 10, Uvn: 115, Unn: 11, Uk: 10.5, Pk: 60},
+	{ID: "ТДН-16000/110", Snom: 16, Uvn: 115, Unn: 11, Uk: 10.5, Pk: 85},
+	{ID: "ТРДН-25000/110", Snom: 25, Uvn: 115, Unn: 10.5, Uk: 10.5, Pk: 120},
+	{ID: "ТМН-6300/35", Snom: 6.3, Uvn: 35, Unn: 11, Uk: 7.5, Pk: 46.5},
+}
+
+func findTransformer(id string) (TransformerType, bool) {
+	for _, t := range transformerCatalog {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return TransformerType{}, false
+}
+
+// ukMax повертає uк для мінімального режиму.
+func (t TransformerType) ukMax() float64 {
+	if t.UkMax > 0 {
+		return t.UkMax
+	}
+	return t.Uk
+}
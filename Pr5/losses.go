@@ -0,0 +1,66 @@
+package main
+
+// Математичне сподівання недовідпущеної електроенергії та збитків від
+// перерв електропостачання:
+//
+//	M(Wнед.а) = ω·tв·Pм·Tм / 8760  — аварійні вимкнення,
+//	M(Wнед.п) = kп·Pм·Tм           — планові вимкнення,
+//	M(Зпер)   = Зпер.а·M(Wнед.а) + Зпер.п·M(Wнед.п).
+
+type Task2Input struct {
+	Omega         float64 `json:"omega"`         // ω, 1/рік
+	Tv            float64 `json:"tv"`            // tв, год
+	Kp            float64 `json:"kp"`            // kп — коефіцієнт планового простою
+	Pm            float64 `json:"pm"`            // Pм, кВт — потужність трансформатора
+	Tm            float64 `json:"tm"`            // Tм, год — час використання максимуму
+	LossEmergency float64 `json:"lossEmergency"` // Зпер.а, грн/кВт·год
+	LossPlanned   float64 `json:"lossPlanned"`   // Зпер.п, грн/кВт·год
+}
+
+// Приклад: ω = 0,01 рік⁻¹, tв = 45·10⁻³ року, kп = 4·10⁻³, Pм = 5120 кВт, Tм = 6451 год.
+var defaultTask2 = Task2Input{
+	Omega:         0.01,
+	Tv:            45e-3 * hoursPerYear,
+	Kp:            4e-3,
+	Pm:            5120,
+	Tm:            6451,
+	LossEmergency: 23.6,
+	LossPlanned:   17.6,
+}
+
+type Task2Result struct {
+	Input Task2Input `json:"input"`
+
+	Ka              float64 `json:"ka"`              // kа = ω·tв/8760
+	Energy          float64 `json:"energy"`          // Pм·Tм, кВт·год — річне споживання
+	EnergyEmergency float64 `json:"energyEmergency"` // M(Wнед.а), кВт·год
+	EnergyPlanned   float64 `json:"energyPlanned"`   // M(Wнед.п), кВт·год
+	LossEmergency   float64 `json:"lossEmergency"`   // Зпер.а·M(Wнед.а), грн
+	LossPlanned     float64 `json:"lossPlanned"`     // Зпер.п·M(Wнед.п), грн
+	Loss            float64 `json:"loss"`            // M(Зпер), грн
+}
+
+// validate перевіряє, що всі величини додатні та правдоподібні.
+func (in Task2Input) validate() error {
+	var errs ValidationErrors
+	errs.checkRange("omega", "ω", in.Omega, 0, maxOmega, false)
+	errs.checkRange("tv", "tв", in.Tv, 0, hoursPerYear, true)
+	errs.checkRange("kp", "kп", in.Kp, 0, 1, false)
+	errs.checkRange("pm", "Pм", in.Pm, 0, maxPower, true)
+	errs.checkRange("tm", "Tм", in.Tm, 0, hoursPerYear, true)
+	errs.checkRange("lossEmergency", "Зпер.а", in.LossEmergency, 0, maxSpecLoss, false)
+	errs.checkRange("lossPlanned", "Зпер.п", in.LossPlanned, 0, maxSpecLoss, false)
+	return errs.err()
+}
+
+func calculateLosses(in Task2Input) Task2Result {
+	res := Task2Result{Input: in}
+	res.Ka = in.Omega * in.Tv / hoursPerYear
+	res.Energy = in.Pm * in.Tm
+	res.EnergyEmergency = res.Ka * res.Energy
+	res.EnergyPlanned = in.Kp * res.Energy
+	res.LossEmergency = in.LossEmergency * res.EnergyEmergency
+	res.LossPlanned = in.LossPlanned * res.EnergyPlanned
+	res.Loss = res.LossEmergency + res.LossPlanned
+	return res
+}
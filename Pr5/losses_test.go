@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLossesExample(t *testing.T) {
+	res := calculateLosses(defaultTask2)
+	approx(t, "kа", res.Ka, 4.5e-4, 1e-9)
+	approx(t, "M(Wнед.а)", res.EnergyEmergency, 14863, 1e-4)
+	approx(t, "M(Wнед.п)", res.EnergyPlanned, 132116, 1e-4)
+	approx(t, "Зпер.а·M(Wнед.а)", res.LossEmergency, 350769, 1e-4)
+	approx(t, "Зпер.п·M(Wнед.п)", res.LossPlanned, 2325250, 1e-4)
+	approx(t, "M(Зпер)", res.Loss, 2676019, 1e-4)
+}
+
+func TestLossesValidation(t *testing.T) {
+	if err := defaultTask2.validate(); err != nil {
+		t.Fatalf("приклад не пройшов перевірку: %v", err)
+	}
+	tests := []struct {
+		field string
+		edit  func(*Task2Input)
+	}{
+		{"omega", func(in *Task2Input) { in.Omega = -0.01 }},
+		{"omega", func(in *Task2Input) { in.Omega = math.NaN() }},
+		{"tv", func(in *Task2Input) { in.Tv = 0 }},
+		{"tv", func(in *Task2Input) { in.Tv = hoursPerYear + 1 }},
+		{"kp", func(in *Task2Input) { in.Kp = 1.5 }},
+		{"pm", func(in *Task2Input) { in.Pm = math.Inf(1) }},
+		{"tm", func(in *Task2Input) { in.Tm = -1 }},
+		{"lossEmergency", func(in *Task2Input) { in.LossEmergency = maxSpecLoss * 2 }},
+		{"lossPlanned", func(in *Task2Input) { in.LossPlanned = math.Inf(-1) }},
+	}
+	for _, tt := range tests {
+		in := defaultTask2
+		tt.edit(&in)
+		if err := in.validate(); !errorList(err).has(tt.field) {
+			t.Errorf("%s: помилка %v", tt.field, err)
+		}
+	}
+}
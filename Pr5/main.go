@@ -1,21 +1,98 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
+// SystemRow — рядок форми складу схеми, значення як введено.
+type SystemRow struct {
+	Type     string
+	Quantity string
+}
+
 type PageData struct {
-	Task1Result string
-	Task2Result string
+	Catalog     []ElementType
+	Rows        []SystemRow
+	Breaker     string
+	Task1       *Task1Result
+	Task1Errors ValidationErrors
+
+	Task2Form   Task2Form
+	Task2       *Task2Result
+	Task2Errors ValidationErrors
+
+	BlockTypes []string
+	RBDRows    []BlockRow
+	RBDJSON    string
+	RBD        *BlockResult
+	RBDErrors  ValidationErrors
+
+	SimForm   SimForm
+	Sim       *SimResult
+	SimErrors ValidationErrors
+}
+
+// SimForm — параметри моделювання Монте-Карло, значення як введено.
+type SimForm struct {
+	Years, Seed, Load, Repair, RepairCV string
+}
+
+// Task2Form — поля форми збитків, значення як введено.
+type Task2Form struct {
+	Omega, Tv, Kp, Pm, Tm, LossEmergency, LossPlanned string
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func defaultTask2Form() Task2Form {
+	d := defaultTask2
+	return Task2Form{
+		Omega:         formatFloat(d.Omega),
+		Tv:            formatFloat(d.Tv),
+		Kp:            formatFloat(d.Kp),
+		Pm:            formatFloat(d.Pm),
+		Tm:            formatFloat(d.Tm),
+		LossEmergency: formatFloat(d.LossEmergency),
+		LossPlanned:   formatFloat(d.LossPlanned),
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	// hours — річний простій, год, за коефіцієнтом готовності
+	"hours": func(availability float64) float64 { return (1 - availability) * hoursPerYear },
+	// dist — рядок таблиці розподілу з назвою та форматом чисел
+	"dist": func(name, format string, d Distribution) struct {
+		Name, Format string
+		Distribution
+	} {
+		return struct {
+			Name, Format string
+			Distribution
+		}{name, format, d}
+	},
 }
 
+// emptyRows — скільки порожніх рядків додається у форму для нових елементів.
+const emptyRows = 3
+
 func main() {
 	http.HandleFunc("/", homePage)
 	http.HandleFunc("/task1", calculateTask1)
 	http.HandleFunc("/task2", calculateTask2)
+	http.HandleFunc("/api/task1", apiTask1)
+	http.HandleFunc("/api/task2", apiTask2)
+	http.HandleFunc("/rbd", calculateRBD)
+	http.HandleFunc("/api/rbd", apiRBD)
+	http.HandleFunc("/montecarlo", simulateRBD)
+	http.HandleFunc("/api/montecarlo", apiMonteCarlo)
 
 	http.Handle("/styles.css", http.FileServer(http.Dir(".")))
 
@@ -27,7 +104,42 @@ func main() {
 }
 
 func renderTemplate(w http.ResponseWriter, data PageData) {
-	tmpl, err := template.ParseFiles("index.html")
+	data.Catalog = elementCatalog
+	if data.Breaker == "" {
+		data.Breaker = defaultBreaker
+	}
+	if data.Rows == nil {
+		for _, e := range defaultSystem {
+			data.Rows = append(data.Rows, SystemRow{Type: e.Type, Quantity: formatFloat(e.Quantity)})
+		}
+	}
+	if data.Task2Form == (Task2Form{}) {
+		data.Task2Form = defaultTask2Form()
+	}
+	for i := 0; i < emptyRows; i++ {
+		data.Rows = append(data.Rows, SystemRow{})
+	}
+
+	data.BlockTypes = []string{"series", "parallel", "koutofn", "element"}
+	if data.RBDRows == nil {
+		data.RBDRows = defaultBlock.Rows()
+		data.RBDJSON = blockJSON(defaultBlock)
+	}
+	for i := 0; i < emptyRows; i++ {
+		data.RBDRows = append(data.RBDRows, BlockRow{})
+	}
+	if data.SimForm == (SimForm{}) {
+		d := defaultSim
+		data.SimForm = SimForm{
+			Years:    strconv.Itoa(d.Years),
+			Seed:     strconv.FormatUint(d.Seed, 10),
+			Load:     strconv.FormatFloat(d.Load, 'f', 1, 64),
+			Repair:   d.Repair,
+			RepairCV: formatFloat(d.RepairCV),
+		}
+	}
+
+	tmpl, err := template.New("index.html").Funcs(templateFuncs).ParseFiles("index.html")
 	if err != nil {
 		http.Error(w, "Не вдалося завантажити index.html", http.StatusInternalServerError)
 		fmt.Println("Template error:", err)
@@ -46,57 +158,279 @@ func homePage(w http.ResponseWriter, r *http.Request) {
 }
 
 func calculateTask1(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
+	if !postOnly(w, r, false) {
+		return
+	}
+	_ = r.ParseForm()
+
+	// рядки без вибраного типу — порожні, їх пропускаємо
+	data := PageData{Rows: []SystemRow{}, Breaker: r.FormValue("breaker")}
+	var elements []SystemElement
+	var errs ValidationErrors
+	types, quantities := r.Form["type"], r.Form["quantity"]
+	for i, t := range types {
+		if t == "" {
+			continue
+		}
+		row := SystemRow{Type: t}
+		if i < len(quantities) {
+			row.Quantity = quantities[i]
+		}
+		data.Rows = append(data.Rows, row)
+		n := len(data.Rows)
+		qty := errs.parseFloat(fmt.Sprintf("elements[%d].quantity", n-1), fmt.Sprintf("Рядок %d, кількість", n), row.Quantity)
+		elements = append(elements, SystemElement{Type: t, Quantity: qty})
+	}
+	if len(errs) > 0 {
+		data.Task1Errors = errs
+		renderTemplate(w, data)
+		return
+	}
+
+	res, err := calculateReliability(elements, data.Breaker)
+	if err != nil {
+		data.Task1Errors = errorList(err)
+	} else {
+		data.Task1 = &res
+	}
+	renderTemplate(w, data)
+}
+
+// apiTask1 — той самий розрахунок для API-клієнтів:
+// {"elements": [{"type": "t110", "quantity": 1}, ...], "breaker": "v10-oil"}.
+func apiTask1(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, true) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req struct {
+		Elements []SystemElement `json:"elements"`
+		Breaker  string          `json:"breaker"`
+	}
+	err := decodeJSON(r, &req)
+	var res Task1Result
+	if err == nil {
+		res, err = calculateReliability(req.Elements, req.Breaker)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func calculateTask2(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, false) {
 		return
 	}
 
-	sum := 0.0
+	f := Task2Form{
+		Omega:         r.FormValue("omega"),
+		Tv:            r.FormValue("tv"),
+		Kp:            r.FormValue("kp"),
+		Pm:            r.FormValue("pm"),
+		Tm:            r.FormValue("tm"),
+		LossEmergency: r.FormValue("loss_emergency"),
+		LossPlanned:   r.FormValue("loss_planned"),
+	}
+	var errs ValidationErrors
+	in := Task2Input{
+		Omega:         errs.parseFloat("omega", "ω", f.Omega),
+		Tv:            errs.parseFloat("tv", "tв", f.Tv),
+		Kp:            errs.parseFloat("kp", "kп", f.Kp),
+		Pm:            errs.parseFloat("pm", "Pм", f.Pm),
+		Tm:            errs.parseFloat("tm", "Tм", f.Tm),
+		LossEmergency: errs.parseFloat("lossEmergency", "Зпер.а", f.LossEmergency),
+		LossPlanned:   errs.parseFloat("lossPlanned", "Зпер.п", f.LossPlanned),
+	}
+	data := PageData{Task2Form: f}
+	// межі перевіряємо лише для полів, що розібралися як числа
+	for _, e := range errorList(in.validate()) {
+		if !errs.has(e.Field) {
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) > 0 {
+		data.Task2Errors = errs
+	} else {
+		res := calculateLosses(in)
+		data.Task2 = &res
+	}
+	renderTemplate(w, data)
+}
 
-	if value := r.FormValue("v110"); value != "" {
-		v, _ := strconv.ParseFloat(value, 64)
-		sum += v
+// apiTask2 — розрахунок збитків для API-клієнтів; незадані поля беруться з прикладу.
+func apiTask2(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, true) {
+		return
 	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
-	if value := r.FormValue("t110"); value != "" {
-		v, _ := strconv.ParseFloat(value, 64)
-		sum += v
+	in := defaultTask2
+	err := decodeJSON(r, &in)
+	if err == nil {
+		err = in.validate()
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
 	}
+	_ = json.NewEncoder(w).Encode(calculateLosses(in))
+}
+
+func blockJSON(b Block) string {
+	out, _ := json.MarshalIndent(b, "", "  ")
+	return string(out)
+}
 
-	if value := r.FormValue("bus10"); value != "" {
-		v, _ := strconv.ParseFloat(value, 64)
-		qty, _ := strconv.ParseFloat(r.FormValue("bus10_quantity"), 64)
-		if qty <= 0 {
-			qty = 1
+// readRBD зчитує структурну схему з конструктора або, якщо натиснуто
+// кнопку JSON, з текстового поля.
+func readRBD(r *http.Request) (PageData, Block, error) {
+	_ = r.ParseForm()
+	data := PageData{RBDJSON: r.FormValue("rbd_json"), RBDRows: []BlockRow{}}
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return values[i]
 		}
-		sum += v * qty
+		return ""
+	}
+	levels := r.Form["level"]
+	for i := range levels {
+		data.RBDRows = append(data.RBDRows, BlockRow{
+			Level:    levels[i],
+			Type:     at(r.Form["btype"], i),
+			Name:     at(r.Form["bname"], i),
+			Element:  at(r.Form["element"], i),
+			Quantity: at(r.Form["bquantity"], i),
+			K:        at(r.Form["k"], i),
+		})
+	}
+	data.SimForm = SimForm{
+		Years:    r.FormValue("years"),
+		Seed:     r.FormValue("seed"),
+		Load:     r.FormValue("load"),
+		Repair:   r.FormValue("repair"),
+		RepairCV: r.FormValue("repair_cv"),
 	}
 
-	if value := r.FormValue("pl110"); value != "" {
-		v, _ := strconv.ParseFloat(value, 64)
-		sum += v
+	if r.FormValue("source") == "json" {
+		b, err := parseBlock([]byte(data.RBDJSON))
+		return data, b, err
 	}
+	b, err := blockFromRows(data.RBDRows)
+	return data, b, err
+}
 
-	if value := r.FormValue("pl10"); value != "" {
-		v, _ := strconv.ParseFloat(value, 64)
-		sum += v
+// calculateRBD згортає структурну схему; обидва подання схеми
+// оновлюються за результатом.
+func calculateRBD(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, false) {
+		return
+	}
+	data, b, err := readRBD(r)
+	var res BlockResult
+	if err == nil {
+		res, err = b.Reduce()
 	}
+	if err != nil {
+		data.RBDErrors = errorList(err)
+		renderTemplate(w, data)
+		return
+	}
+	data.RBD = &res
+	data.RBDRows = b.Rows()
+	data.RBDJSON = blockJSON(b)
+	renderTemplate(w, data)
+}
 
-	result := fmt.Sprintf("Показник надійності одноколової системи: %.4f", sum)
-	renderTemplate(w, PageData{Task1Result: result})
+// simulateRBD — аналітична згортка та моделювання Монте-Карло тієї ж схеми.
+func simulateRBD(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, false) {
+		return
+	}
+	data, b, err := readRBD(r)
+	var res BlockResult
+	if err == nil {
+		res, err = b.Reduce()
+	}
+	if err != nil {
+		data.RBDErrors = errorList(err)
+		renderTemplate(w, data)
+		return
+	}
+	data.RBD = &res
+	data.RBDRows = b.Rows()
+	data.RBDJSON = blockJSON(b)
+
+	f := data.SimForm
+	var errs ValidationErrors
+	years := errs.parseInt("years", "Кількість років", f.Years)
+	seed, err := strconv.ParseUint(strings.TrimSpace(f.Seed), 10, 64)
+	if err != nil {
+		errs.add("seed", "Seed: %q має бути невід'ємним цілим числом", f.Seed)
+	}
+	load := errs.parseFloat("load", "Навантаження", f.Load)
+	cv := errs.parseFloat("repairCV", "Коефіцієнт варіації", f.RepairCV)
+	if len(errs) > 0 {
+		data.SimErrors = errs
+		renderTemplate(w, data)
+		return
+	}
+	sim, err := simulate(SimInput{Block: b, Years: years, Seed: seed, Load: load, Repair: f.Repair, RepairCV: cv})
+	if err != nil {
+		data.SimErrors = errorList(err)
+	} else {
+		data.Sim = &sim
+	}
+	renderTemplate(w, data)
 }
 
-func calculateTask2(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
+// apiRBD — згортка схеми, переданої JSON-ом у тілі запиту.
+func apiRBD(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, true) {
 		return
 	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
-	lossEmergency, _ := strconv.ParseFloat(r.FormValue("loss_emergency"), 64)
-	lossPlanned, _ := strconv.ParseFloat(r.FormValue("loss_planned"), 64)
+	raw, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	var b Block
+	if err == nil {
+		b, err = parseBlock(raw)
+	}
+	var res BlockResult
+	if err == nil {
+		res, err = b.Reduce()
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
 
-	totalLoss := lossEmergency + lossPlanned
+// apiMonteCarlo — моделювання для API-клієнтів: {"block": {...}, "years": …};
+// незадані параметри беруться з прикладу.
+func apiMonteCarlo(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r, true) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
-	result := fmt.Sprintf("Загальні збитки від перерв електропостачання: %.2f грн/кВт*год", totalLoss)
-	renderTemplate(w, PageData{Task2Result: result})
-}
\ No newline at end of file
+	in := defaultSim
+	err := decodeJSON(r, &in)
+	if err == nil && in.Block.isZero() {
+		// схема не входить у defaultSim: декодування поверх прикладу
+		// змішало б задану схему з його складовими
+		in.Block = defaultBlock.clone()
+	}
+	var res SimResult
+	if err == nil {
+		res, err = simulate(in)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+// Імітаційне моделювання структурної схеми методом Монте-Карло. Кожен
+// елемент по черзі працює (час до відмови — експоненційний з λ = ω·кількість)
+// та відновлюється (розподіл часу відновлення задається), стан системи
+// визначається за схемою після кожної події. Моделювання ділиться на
+// mcChunks незалежних відрізків, кожен зі своїм генератором від seed, що
+// виконуються паралельно, тож результат залежить лише від seed і не
+// залежить від кількості процесорів.
+
+const (
+	mcChunks    = 8
+	mcMaxYears  = 100000
+	mcMaxEvents = 20_000_000
+	mcZ95       = 1.959964 // квантиль нормального розподілу для 95 % інтервалу
+)
+
+type SimInput struct {
+	Block    Block   `json:"block"`
+	Years    int     `json:"years"`
+	Seed     uint64  `json:"seed"`
+	Load     float64 `json:"load"`     // середнє навантаження, кВт — для недовідпущеної енергії
+	Repair   string  `json:"repair"`   // exp | lognormal | const
+	RepairCV float64 `json:"repairCV"` // коефіцієнт варіації для lognormal
+}
+
+// Приклад: 10 000 років, навантаження Pм·Tм/8760 із задачі збитків;
+// схема за замовчуванням — defaultBlock.
+var defaultSim = SimInput{
+	Years:    10000,
+	Seed:     1,
+	Load:     defaultTask2.Pm * defaultTask2.Tm / hoursPerYear,
+	Repair:   "exp",
+	RepairCV: 0.5,
+}
+
+// Distribution — вибіркові характеристики річного показника.
+type Distribution struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+	CILow  float64 `json:"ciLow"` // 95 % довірчий інтервал середнього
+	CIHigh float64 `json:"ciHigh"`
+	P5     float64 `json:"p5"`
+	P50    float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+}
+
+type SimResult struct {
+	Input        SimInput     `json:"input"`
+	Availability Distribution `json:"availability"` // за роками
+	Outages      Distribution `json:"outages"`      // кількість перерв за рік
+	Downtime     Distribution `json:"downtime"`     // год/рік
+	Energy       Distribution `json:"energy"`       // недовідпущена енергія, кВт·год/рік
+
+	// OutageYears[i] — частка років з i перервами, останній елемент — i і більше
+	OutageYears []float64 `json:"outageYears"`
+}
+
+// simNode — скомпільована схема: leaf ≥ 0 — індекс елемента, інакше
+// блок, що працює, коли працюють щонайменше k складових.
+type simNode struct {
+	leaf     int
+	k        int
+	children []simNode
+}
+
+type simElement struct {
+	lambda float64 // 1/год
+	tv     float64 // год
+}
+
+func compileBlock(b Block, field string, elements *[]simElement) (simNode, error) {
+	if b.Type == "element" {
+		e, qty, err := b.elementParams(field)
+		if err != nil {
+			return simNode{}, err
+		}
+		*elements = append(*elements, simElement{lambda: e.Omega * qty / hoursPerYear, tv: e.Tv})
+		return simNode{leaf: len(*elements) - 1}, nil
+	}
+	node := simNode{leaf: -1}
+	for i, c := range b.Blocks {
+		child, err := compileBlock(c, fmt.Sprintf("%s.blocks[%d]", field, i), elements)
+		if err != nil {
+			return node, err
+		}
+		node.children = append(node.children, child)
+	}
+	switch b.Type {
+	case "series":
+		node.k = len(node.children)
+	case "parallel":
+		node.k = 1
+	default:
+		node.k = b.K
+	}
+	return node, nil
+}
+
+func (n simNode) up(state []bool) bool {
+	if n.leaf >= 0 {
+		return state[n.leaf]
+	}
+	count := 0
+	for _, c := range n.children {
+		if c.up(state) {
+			count++
+			if count >= n.k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// yearStats — накопичені за один модельний рік показники.
+type yearStats struct {
+	downtime float64
+	outages  int
+}
+
+// repairTime повертає час відновлення із заданим середнім tv.
+func repairTime(rng *rand.Rand, tv float64, kind string, cv float64) float64 {
+	switch kind {
+	case "const":
+		return tv
+	case "lognormal":
+		sigma2 := math.Log(1 + cv*cv)
+		mu := math.Log(tv) - sigma2/2
+		return math.Exp(mu + math.Sqrt(sigma2)*rng.NormFloat64())
+	}
+	return rng.ExpFloat64() * tv
+}
+
+// yearOf — номер модельного року для моменту t, обмежений діапазоном [0, years).
+func yearOf(t float64, years int) int {
+	y := t / hoursPerYear
+	if !(y >= 0) {
+		return 0
+	}
+	return int(math.Min(y, float64(years-1)))
+}
+
+// simulateChunk моделює years послідовних років від стану «все працює».
+func simulateChunk(root simNode, elements []simElement, in SimInput, years int, rng *rand.Rand) []yearStats {
+	stats := make([]yearStats, years)
+	horizon := float64(years) * hoursPerYear
+
+	state := make([]bool, len(elements))
+	next := make([]float64, len(elements))
+	for i, e := range elements {
+		state[i] = true
+		next[i] = rng.ExpFloat64() / e.lambda
+	}
+
+	// addDowntime розносить інтервал простою [from, to) по роках
+	addDowntime := func(from, to float64) {
+		for from < to {
+			y := int(from / hoursPerYear)
+			if y >= years {
+				return
+			}
+			end := math.Min(to, float64(y+1)*hoursPerYear)
+			stats[y].downtime += end - from
+			from = end
+		}
+	}
+
+	systemUp := true
+	downSince := 0.0
+	for {
+		i := 0
+		for j := range next {
+			if next[j] < next[i] {
+				i = j
+			}
+		}
+		t := next[i]
+		if t >= horizon {
+			break
+		}
+		e := elements[i]
+		if state[i] {
+			state[i] = false
+			next[i] = t + repairTime(rng, e.tv, in.Repair, in.RepairCV)
+		} else {
+			state[i] = true
+			next[i] = t + rng.ExpFloat64()/e.lambda
+		}
+
+		if up := root.up(state); up != systemUp {
+			systemUp = up
+			if !up {
+				downSince = t
+				stats[yearOf(t, years)].outages++
+			} else {
+				addDowntime(downSince, t)
+			}
+		}
+	}
+	if !systemUp {
+		addDowntime(downSince, horizon)
+	}
+	return stats
+}
+
+func (in SimInput) validate() error {
+	var errs ValidationErrors
+	if in.Years < 1 || in.Years > mcMaxYears {
+		errs.add("years", "Кількість років має бути від 1 до %d", mcMaxYears)
+	}
+	errs.checkRange("load", "Навантаження", in.Load, 0, maxPower, false)
+	switch in.Repair {
+	case "exp", "const":
+	case "lognormal":
+		errs.checkRange("repairCV", "Коефіцієнт варіації", in.RepairCV, 0, maxRepairCV, true)
+	default:
+		errs.add("repair", "Невідомий розподіл часу відновлення %q", in.Repair)
+	}
+	return errs.err()
+}
+
+func simulate(in SimInput) (SimResult, error) {
+	if in.Repair == "" {
+		in.Repair = "exp"
+	}
+	if err := in.validate(); err != nil {
+		return SimResult{}, err
+	}
+	// перевірка структури та параметрів — тією ж згорткою, що й аналітичний розрахунок
+	if _, err := in.Block.Reduce(); err != nil {
+		return SimResult{}, err
+	}
+	var elements []simElement
+	root, err := compileBlock(in.Block, "block", &elements)
+	if err != nil {
+		return SimResult{}, err
+	}
+
+	// нескінченні чи нульові інтенсивності зупинили б моделювання
+	// посеред горутини, тож перевіряються до її запуску
+	totalLambda := 0.0
+	for i, e := range elements {
+		if !(e.lambda > 0) || math.IsInf(e.lambda, 0) || !(e.tv >= 0) || math.IsInf(e.tv, 0) {
+			return SimResult{}, fmt.Errorf("елемент %d схеми: некоректні параметри (λ = %g 1/год, tв = %g год)", i+1, e.lambda, e.tv)
+		}
+		totalLambda += e.lambda * hoursPerYear
+	}
+	if totalLambda*2*float64(in.Years) > mcMaxEvents {
+		return SimResult{}, fmt.Errorf("забагато подій для моделювання (≈%.0f), зменшіть кількість років", totalLambda*2*float64(in.Years))
+	}
+
+	chunks := make([][]yearStats, mcChunks)
+	var wg sync.WaitGroup
+	for c := 0; c < mcChunks; c++ {
+		years := in.Years / mcChunks
+		if c < in.Years%mcChunks {
+			years++
+		}
+		wg.Add(1)
+		go func(c, years int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(in.Seed, uint64(c)))
+			chunks[c] = simulateChunk(root, elements, in, years, rng)
+		}(c, years)
+	}
+	wg.Wait()
+
+	var all []yearStats
+	for _, s := range chunks {
+		all = append(all, s...)
+	}
+
+	res := SimResult{Input: in, OutageYears: make([]float64, 4)}
+	avail := make([]float64, len(all))
+	outages := make([]float64, len(all))
+	downtime := make([]float64, len(all))
+	energy := make([]float64, len(all))
+	for i, s := range all {
+		avail[i] = 1 - s.downtime/hoursPerYear
+		outages[i] = float64(s.outages)
+		downtime[i] = s.downtime
+		energy[i] = s.downtime * in.Load
+		res.OutageYears[min(s.outages, len(res.OutageYears)-1)]++
+	}
+	for i := range res.OutageYears {
+		res.OutageYears[i] /= float64(len(all))
+	}
+	res.Availability = describe(avail)
+	res.Outages = describe(outages)
+	res.Downtime = describe(downtime)
+	res.Energy = describe(energy)
+	return res, nil
+}
+
+func describe(values []float64) Distribution {
+	n := float64(len(values))
+	var d Distribution
+	for _, v := range values {
+		d.Mean += v
+	}
+	d.Mean /= n
+	for _, v := range values {
+		d.StdDev += (v - d.Mean) * (v - d.Mean)
+	}
+	if n > 1 {
+		d.StdDev = math.Sqrt(d.StdDev / (n - 1))
+	}
+	half := mcZ95 * d.StdDev / math.Sqrt(n)
+	d.CILow, d.CIHigh = d.Mean-half, d.Mean+half
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	q := func(p float64) float64 { return sorted[int(p*(n-1))] }
+	d.P5, d.P50, d.P95, d.Max = q(0.05), q(0.5), q(0.95), sorted[len(sorted)-1]
+	return d
+}
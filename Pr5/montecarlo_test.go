@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// Моделювання прикладу з фіксованим seed має узгоджуватися зі згорткою.
+func TestSimulateAgreesWithReduce(t *testing.T) {
+	want, err := defaultBlock.Reduce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := defaultSim
+	in.Block = defaultBlock.clone()
+	res, err := simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := res.Availability; want.Availability < a.CILow || want.Availability > a.CIHigh {
+		t.Errorf("A = %.8f поза 95 %% інтервалом [%.8f, %.8f]", want.Availability, a.CILow, a.CIHigh)
+	}
+	if f := res.Outages; want.Frequency < f.CILow || want.Frequency > f.CIHigh {
+		t.Errorf("f = %.5f поза 95 %% інтервалом [%.5f, %.5f]", want.Frequency, f.CILow, f.CIHigh)
+	}
+
+	again, err := simulate(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Availability != res.Availability || again.Outages != res.Outages {
+		t.Error("результат з тим самим seed відрізняється")
+	}
+}
+
+func TestSimulateRejectsBadElement(t *testing.T) {
+	saved := elementCatalog
+	defer func() { elementCatalog = saved }()
+	for _, bad := range []ElementType{
+		{ID: "x", Name: "X", Omega: math.NaN(), Tv: 10},
+		{ID: "x", Name: "X", Omega: 0, Tv: 10},
+		{ID: "x", Name: "X", Omega: 0.01, Tv: math.Inf(1)},
+		{ID: "x", Name: "X", Omega: 0.01, Tv: -1},
+	} {
+		elementCatalog = append(saved[:len(saved):len(saved)], bad)
+		in := SimInput{Block: Block{Type: "element", Element: "x"}, Years: 10, Repair: "exp"}
+		if _, err := simulate(in); err == nil {
+			t.Errorf("ω = %g, tв = %g: помилки немає", bad.Omega, bad.Tv)
+		}
+	}
+}
+
+func TestYearOf(t *testing.T) {
+	tests := []struct {
+		t    float64
+		want int
+	}{{0, 0}, {hoursPerYear * 2.5, 2}, {hoursPerYear * 10, 9}, {math.Inf(1), 9}, {-1, 0}}
+	for _, tt := range tests {
+		if got := yearOf(tt.t, 10); got != tt.want {
+			t.Errorf("yearOf(%g) = %d, want %d", tt.t, got, tt.want)
+		}
+	}
+}
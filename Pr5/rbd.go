@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Структурна схема надійності: елементи каталогу, об'єднані в послідовні
+// (series), паралельні (parallel) та «k з n» (koutofn) блоки довільної
+// вкладеності. Кожен елемент — двостанова модель з інтенсивностями відмов
+// λ = ω·кількість та відновлення 8760/tв, елементи незалежні; планові
+// ремонти не враховуються.
+//
+// Блок згортається точно: коефіцієнт готовності — ймовірність того, що
+// працює щонайменше k з n складових, частота відмов — сума по складових
+// λi·Ai·P(складова i критична), звідки MTTR = U/f, MTBF = 1/f.
+// Послідовне з'єднання — частковий випадок k = n, паралельне — k = 1.
+
+const (
+	maxBlockDepth = 16
+	maxBlocks     = 500
+)
+
+type Block struct {
+	Type     string  `json:"type"` // element | series | parallel | koutofn
+	Name     string  `json:"name,omitempty"`
+	Element  string  `json:"element,omitempty"`  // ID з elementCatalog
+	Quantity float64 `json:"quantity,omitempty"` // для element, за замовчуванням 1
+	K        int     `json:"k,omitempty"`        // для koutofn
+	Blocks   []Block `json:"blocks,omitempty"`
+}
+
+type BlockResult struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	K            int           `json:"k,omitempty"`
+	Availability float64       `json:"availability"`
+	Frequency    float64       `json:"frequency"` // f, відмов за рік
+	Lambda       float64       `json:"lambda"`    // λ = f/A, 1/рік роботи
+	MTTR         float64       `json:"mttr"`      // год
+	MTBF         float64       `json:"mtbf"`      // год
+	Blocks       []BlockResult `json:"blocks,omitempty"`
+}
+
+// Приклад: два однакові кола В-110 — ПЛ-110 — Т-110 — В-10 паралельно,
+// послідовно зі збірними шинами 10 кВ.
+var defaultBlock = Block{Type: "series", Name: "Схема", Blocks: []Block{
+	{Type: "parallel", Name: "Два кола", Blocks: []Block{
+		{Type: "series", Name: "Коло 1", Blocks: defaultCircuit()},
+		{Type: "series", Name: "Коло 2", Blocks: defaultCircuit()},
+	}},
+	{Type: "element", Element: "bus10", Quantity: 6},
+}}
+
+func defaultCircuit() []Block {
+	var blocks []Block
+	for _, e := range defaultSystem[:4] {
+		blocks = append(blocks, Block{Type: "element", Element: e.Type, Quantity: e.Quantity})
+	}
+	return blocks
+}
+
+func parseBlock(data []byte) (Block, error) {
+	var b Block
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return b, fmt.Errorf("некоректний JSON схеми: %w", err)
+	}
+	return b, nil
+}
+
+// isZero — схему не задано.
+func (b Block) isZero() bool {
+	return b.Type == "" && b.Name == "" && b.Element == "" && b.Quantity == 0 && b.K == 0 && len(b.Blocks) == 0
+}
+
+// clone — глибока копія схеми.
+func (b Block) clone() Block {
+	if b.Blocks != nil {
+		blocks := make([]Block, len(b.Blocks))
+		for i, c := range b.Blocks {
+			blocks[i] = c.clone()
+		}
+		b.Blocks = blocks
+	}
+	return b
+}
+
+func (b Block) label() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	if b.Type == "element" {
+		if e, ok := findElement(b.Element); ok {
+			return e.Name
+		}
+		return b.Element
+	}
+	return b.Type
+}
+
+// Reduce перевіряє схему та згортає її.
+func (b Block) Reduce() (BlockResult, error) {
+	count := 0
+	return b.reduce("block", 0, &count)
+}
+
+// elementParams перевіряє блок-елемент і повертає тип із каталогу та
+// кількість (незадана — одна одиниця). Спільна для згортки та моделювання;
+// field — шлях блоку у JSON-запиті.
+func (b Block) elementParams(field string) (ElementType, float64, error) {
+	var errs ValidationErrors
+	e, ok := findElement(b.Element)
+	if !ok {
+		errs.add(field+".element", "Елемент %q відсутній у каталозі", b.Element)
+		return e, 0, errs
+	}
+	qty := b.Quantity
+	if qty == 0 {
+		qty = 1
+	}
+	errs.checkRange(field+".quantity", e.Name+", кількість", qty, 0, maxQuantity, true)
+	return e, qty, errs.err()
+}
+
+func (b Block) reduce(field string, depth int, count *int) (BlockResult, error) {
+	*count++
+	if *count > maxBlocks {
+		return BlockResult{}, fieldError(field, "Схема містить понад %d блоків", maxBlocks)
+	}
+	if depth > maxBlockDepth {
+		return BlockResult{}, fieldError(field, "Вкладеність схеми перевищує %d рівнів", maxBlockDepth)
+	}
+	res := BlockResult{Name: b.label(), Type: b.Type}
+
+	if b.Type == "element" {
+		e, qty, err := b.elementParams(field)
+		if err != nil {
+			return res, err
+		}
+		lambda := e.Omega * qty
+		u := lambda * e.Tv / (hoursPerYear + lambda*e.Tv)
+		res.Availability = 1 - u
+		res.Lambda = lambda
+		res.Frequency = lambda * res.Availability
+		res.finish()
+		return res, nil
+	}
+
+	if len(b.Blocks) == 0 {
+		return res, fieldError(field+".blocks", "Блок «%s» не містить жодної складової", res.Name)
+	}
+	for i, child := range b.Blocks {
+		r, err := child.reduce(fmt.Sprintf("%s.blocks[%d]", field, i), depth+1, count)
+		if err != nil {
+			return res, err
+		}
+		res.Blocks = append(res.Blocks, r)
+	}
+
+	n := len(res.Blocks)
+	switch b.Type {
+	case "series":
+		res.K = n
+	case "parallel":
+		res.K = 1
+	case "koutofn":
+		if b.K < 1 || b.K > n {
+			return res, fieldError(field+".k", "Блок «%s»: k має бути від 1 до %d", res.Name, n)
+		}
+		res.K = b.K
+	default:
+		return res, fieldError(field+".type", "Блок «%s»: невідомий тип %q", res.Name, b.Type)
+	}
+	res.Availability, res.Frequency = kOutOfN(res.Blocks, res.K)
+	if res.Availability > 0 {
+		res.Lambda = res.Frequency / res.Availability
+	}
+	res.finish()
+	if b.Type != "koutofn" {
+		res.K = 0
+	}
+	return res, nil
+}
+
+// finish обчислює середні часи за A та f.
+func (r *BlockResult) finish() {
+	if r.Frequency > 0 {
+		r.MTTR = (1 - r.Availability) * hoursPerYear / r.Frequency
+		r.MTBF = hoursPerYear / r.Frequency
+	}
+}
+
+// upProbabilities — розподіл кількості працюючих серед blocks (крім skip).
+func upProbabilities(blocks []BlockResult, skip int) []float64 {
+	p := []float64{1}
+	for i, b := range blocks {
+		if i == skip {
+			continue
+		}
+		next := make([]float64, len(p)+1)
+		for m, v := range p {
+			next[m] += v * (1 - b.Availability)
+			next[m+1] += v * b.Availability
+		}
+		p = next
+	}
+	return p
+}
+
+func kOutOfN(blocks []BlockResult, k int) (availability, frequency float64) {
+	for m, v := range upProbabilities(blocks, -1) {
+		if m >= k {
+			availability += v
+		}
+	}
+	// складова i критична, якщо з решти працюють рівно k−1
+	for i, b := range blocks {
+		others := upProbabilities(blocks, i)
+		if k-1 < len(others) {
+			frequency += b.Lambda * b.Availability * others[k-1]
+		}
+	}
+	return availability, frequency
+}
+
+// ----- табличний конструктор -----
+
+// BlockRow — рядок конструктора: рівень вкладеності задає структуру, як
+// відступи у списку — рядок рівня L+1 після блоку рівня L є його складовою.
+type BlockRow struct {
+	Level, Type, Name, Element, Quantity, K string
+}
+
+// Rows розгортає схему в рядки конструктора.
+func (b Block) Rows() []BlockRow {
+	var rows []BlockRow
+	var walk func(b Block, level int)
+	walk = func(b Block, level int) {
+		row := BlockRow{Level: strconv.Itoa(level), Type: b.Type, Name: b.Name, Element: b.Element}
+		if b.Quantity != 0 {
+			row.Quantity = formatFloat(b.Quantity)
+		}
+		if b.K != 0 {
+			row.K = strconv.Itoa(b.K)
+		}
+		rows = append(rows, row)
+		for _, c := range b.Blocks {
+			walk(c, level+1)
+		}
+	}
+	walk(b, 0)
+	return rows
+}
+
+// field — шлях поля рядка i у повідомленні про помилку.
+func (BlockRow) field(i int, name string) string {
+	return fmt.Sprintf("rows[%d].%s", i, name)
+}
+
+// blockFromRows будує схему з рядків конструктора; рядки без типу пропускаються.
+func blockFromRows(rows []BlockRow) (Block, error) {
+	type frame struct {
+		level int
+		block *Block
+	}
+	var root *Block
+	var stack []frame
+	for i, row := range rows {
+		if row.Type == "" {
+			continue
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(row.Level))
+		if err != nil || level < 0 {
+			return Block{}, fieldError(row.field(i, "level"), "Рядок %d: рівень має бути цілим числом від 0", i+1)
+		}
+		b := Block{Type: row.Type, Name: strings.TrimSpace(row.Name)}
+		if row.Type == "element" {
+			b.Element = row.Element
+			if row.Quantity != "" {
+				var errs ValidationErrors
+				b.Quantity = errs.parseFloat(row.field(i, "quantity"), fmt.Sprintf("Рядок %d, кількість", i+1), row.Quantity)
+				if len(errs) > 0 {
+					return Block{}, errs
+				}
+			}
+		}
+		if row.Type == "koutofn" {
+			if b.K, err = strconv.Atoi(row.K); err != nil {
+				return Block{}, fieldError(row.field(i, "k"), "Рядок %d: k має бути цілим числом", i+1)
+			}
+		}
+
+		if root == nil {
+			if level != 0 {
+				return Block{}, fieldError(row.field(i, "level"), "Рядок %d: перший блок має бути рівня 0", i+1)
+			}
+			root = new(Block)
+			*root = b
+			stack = []frame{{0, root}}
+			continue
+		}
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			return Block{}, fieldError(row.field(i, "level"), "Рядок %d: схема може мати лише один блок рівня 0", i+1)
+		}
+		parent := stack[len(stack)-1]
+		if parent.level != level-1 {
+			return Block{}, fieldError(row.field(i, "level"), "Рядок %d: рівень %d не може йти після рівня %d", i+1, level, parent.level)
+		}
+		if parent.block.Type == "element" {
+			return Block{}, fieldError(row.field(i, "level"), "Рядок %d: елемент не може містити складових", i+1)
+		}
+		parent.block.Blocks = append(parent.block.Blocks, b)
+		stack = append(stack, frame{level, &parent.block.Blocks[len(parent.block.Blocks)-1]})
+	}
+	if root == nil {
+		return Block{}, fieldError("rows", "Схема не містить жодного блоку")
+	}
+	return *root, nil
+}
+
+// FlatResult — рядок таблиці результатів; Indent — відступ, px.
+type FlatResult struct {
+	Indent int
+	BlockResult
+}
+
+// Flat розгортає результати згортки в рядки таблиці.
+func (r BlockResult) Flat() []FlatResult {
+	var out []FlatResult
+	var walk func(r BlockResult, level int)
+	walk = func(r BlockResult, level int) {
+		out = append(out, FlatResult{Indent: level * 20, BlockResult: r})
+		for _, c := range r.Blocks {
+			walk(c, level+1)
+		}
+	}
+	walk(r, 0)
+	return out
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// elementAF — готовність і частота відмов окремого елемента.
+func elementAF(id string, qty float64) (a, f float64) {
+	e, _ := findElement(id)
+	lambda := e.Omega * qty
+	a = 1 - lambda*e.Tv/(hoursPerYear+lambda*e.Tv)
+	return a, lambda * a
+}
+
+func TestReduceSeriesParallel(t *testing.T) {
+	a1, f1 := elementAF("pl110", 10)
+	a2, f2 := elementAF("t110", 1)
+	el := func(id string, qty float64) Block { return Block{Type: "element", Element: id, Quantity: qty} }
+
+	series, err := Block{Type: "series", Blocks: []Block{el("pl110", 10), el("t110", 0)}}.Reduce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A = ΠAi, f = Σλi·A
+	approx(t, "A посл.", series.Availability, a1*a2, 1e-12)
+	approx(t, "f посл.", series.Frequency, (f1/a1+f2/a2)*a1*a2, 1e-12)
+
+	parallel, err := Block{Type: "parallel", Blocks: []Block{el("pl110", 10), el("t110", 1)}}.Reduce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A = 1 − Π(1 − Ai), f = Σfi·Π(1 − Aj), j ≠ i
+	approx(t, "A пар.", parallel.Availability, 1-(1-a1)*(1-a2), 1e-12)
+	approx(t, "f пар.", parallel.Frequency, f1*(1-a2)+f2*(1-a1), 1e-12)
+	approx(t, "MTTR пар.", parallel.MTTR, (1-parallel.Availability)*hoursPerYear/parallel.Frequency, 1e-12)
+
+	// «k з n» при k = n та k = 1 збігається з послідовним і паралельним
+	for k, want := range map[int]BlockResult{2: series, 1: parallel} {
+		r, err := Block{Type: "koutofn", K: k, Blocks: []Block{el("pl110", 10), el("t110", 1)}}.Reduce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		approx(t, "A k з n", r.Availability, want.Availability, 1e-12)
+		approx(t, "f k з n", r.Frequency, want.Frequency, 1e-12)
+	}
+}
+
+// Два кола прикладу паралельно, послідовно із шинами 10 кВ.
+func TestReduceExample(t *testing.T) {
+	res, err := defaultBlock.Reduce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx(t, "A", res.Availability, 0.99995880, 1e-8)
+	approx(t, "f", res.Frequency, 0.18006606, 1e-6)
+	if len(res.Blocks) != 2 || len(res.Blocks[0].Blocks) != 2 {
+		t.Fatalf("структура результату не відповідає схемі: %+v", res)
+	}
+}
+
+func TestReduceQuantity(t *testing.T) {
+	tests := []struct {
+		name string
+		qty  float64
+	}{
+		{"NaN", math.NaN()},
+		{"Inf", math.Inf(1)},
+		{"від'ємна", -1},
+		{"понад межу", maxQuantity + 1},
+	}
+	for _, tt := range tests {
+		b := defaultBlock.clone()
+		b.Blocks[0].Blocks[1].Blocks[1].Quantity = tt.qty
+		const field = "block.blocks[0].blocks[1].blocks[1].quantity"
+		if _, err := b.Reduce(); !errorList(err).has(field) {
+			t.Errorf("%s: Reduce: %v", tt.name, err)
+		}
+		if _, err := simulate(SimInput{Block: b, Years: 10, Repair: "exp"}); !errorList(err).has(field) {
+			t.Errorf("%s: simulate: %v", tt.name, err)
+		}
+		var elements []simElement
+		if _, err := compileBlock(b, "block", &elements); !errorList(err).has(field) {
+			t.Errorf("%s: compileBlock: %v", tt.name, err)
+		}
+	}
+}
+
+func TestBlockFromRowsNaN(t *testing.T) {
+	b, err := blockFromRows([]BlockRow{
+		{Level: "0", Type: "series"},
+		{Level: "1", Type: "element", Element: "pl110", Quantity: "NaN"},
+	})
+	if err == nil {
+		_, err = b.Reduce()
+	}
+	if err == nil {
+		t.Fatal("кількість NaN прийнято")
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// hoursPerYear — кількість годин у році для переходу від ω·tв до коефіцієнтів.
+const hoursPerYear = 8760
+
+// ElementType — рядок довідкової таблиці показників надійності елементів.
+// Для ліній ω задано на 1 км, для збірних шин — на одне приєднання.
+type ElementType struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Unit  string  `json:"unit"`  // шт | км | приєднань
+	Omega float64 `json:"omega"` // ω, 1/рік
+	Tv    float64 `json:"tv"`    // tв, год — середній час відновлення
+	Mu    float64 `json:"mu"`    // μ, 1/рік — частота планових ремонтів
+	Tp    float64 `json:"tp"`    // tп, год — середня тривалість планового ремонту
+
+	Breaker bool `json:"breaker,omitempty"` // може бути секційним вимикачем
+}
+
+var elementCatalog = []ElementType{
+	{ID: "pl110", Name: "ПЛ-110 кВ", Unit: "км", Omega: 0.007, Tv: 10, Mu: 0.167, Tp: 35},
+	{ID: "pl35", Name: "ПЛ-35 кВ", Unit: "км", Omega: 0.02, Tv: 8, Mu: 0.167, Tp: 35},
+	{ID: "pl10", Name: "ПЛ-10 кВ", Unit: "км", Omega: 0.02, Tv: 10, Mu: 0.167, Tp: 35},
+	{ID: "kl10-trench", Name: "КЛ-10 кВ (траншея)", Unit: "км", Omega: 0.03, Tv: 44, Mu: 1.0, Tp: 9},
+	{ID: "kl10-channel", Name: "КЛ-10 кВ (кабельний канал)", Unit: "км", Omega: 0.005, Tv: 17.5, Mu: 1.0, Tp: 9},
+	{ID: "t110", Name: "Т-110 кВ", Unit: "шт", Omega: 0.015, Tv: 100, Mu: 1.0, Tp: 43},
+	{ID: "t35", Name: "Т-35 кВ", Unit: "шт", Omega: 0.02, Tv: 80, Mu: 1.0, Tp: 28},
+	{ID: "t10-cable", Name: "Т-10 кВ (кабельна мережа 10 кВ)", Unit: "шт", Omega: 0.005, Tv: 60, Mu: 0.5, Tp: 10},
+	{ID: "t10-overhead", Name: "Т-10 кВ (повітряна мережа 10 кВ)", Unit: "шт", Omega: 0.05, Tv: 60, Mu: 0.5, Tp: 10},
+	{ID: "v110", Name: "В-110 кВ (елегазовий)", Unit: "шт", Omega: 0.01, Tv: 30, Mu: 0.1, Tp: 30, Breaker: true},
+	{ID: "v10-oil", Name: "В-10 кВ (малооливний)", Unit: "шт", Omega: 0.02, Tv: 15, Mu: 0.33, Tp: 15, Breaker: true},
+	{ID: "v10-vacuum", Name: "В-10 кВ (вакуумний)", Unit: "шт", Omega: 0.05, Tv: 15, Mu: 0.33, Tp: 15, Breaker: true},
+	{ID: "bus10", Name: "Збірні шини 10 кВ", Unit: "приєднань", Omega: 0.03, Tv: 2, Mu: 0.167, Tp: 5},
+	{ID: "av038", Name: "АВ-0,38 кВ", Unit: "шт", Omega: 0.05, Tv: 4, Mu: 1.0, Tp: 10, Breaker: true},
+	{ID: "ed10", Name: "ЕД 6, 10 кВ", Unit: "шт", Omega: 0.1, Tv: 160, Mu: 0.5, Tp: 0},
+	{ID: "ed038", Name: "ЕД 0,38 кВ", Unit: "шт", Omega: 0.1, Tv: 50, Mu: 0.5, Tp: 0},
+}
+
+func findElement(id string) (ElementType, bool) {
+	for _, e := range elementCatalog {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return ElementType{}, false
+}
+
+// SystemElement — елемент схеми: тип із каталогу та кількість (штук,
+// кілометрів або приєднань — див. ElementType.Unit).
+type SystemElement struct {
+	Type     string  `json:"type"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Приклад одноколової схеми: В-110, ПЛ-110 10 км, Т-110, В-10, шини на 6 приєднань.
+var defaultSystem = []SystemElement{
+	{Type: "v110", Quantity: 1},
+	{Type: "pl110", Quantity: 10},
+	{Type: "t110", Quantity: 1},
+	{Type: "v10-oil", Quantity: 1},
+	{Type: "bus10", Quantity: 6},
+}
+
+type ElementLine struct {
+	Element  ElementType `json:"element"`
+	Quantity float64     `json:"quantity"`
+	Omega    float64     `json:"omega"`   // ω·кількість, 1/рік
+	OmegaTv  float64     `json:"omegaTv"` // ω·tв, год/рік
+	Kp       float64     `json:"kp"`      // μ·tп/8760
+}
+
+// SingleCircuit — показники одноколової (послідовної) системи.
+type SingleCircuit struct {
+	Lines []ElementLine `json:"lines"`
+	Omega float64       `json:"omega"` // ωос, 1/рік
+	Tv    float64       `json:"tv"`    // tв.ос, год
+	Ka    float64       `json:"ka"`    // kа.ос — коефіцієнт аварійного простою
+	Kp    float64       `json:"kp"`    // kп.ос — коефіцієнт планового простою
+
+	// Availability — частка часу, коли система живить споживачів: одноколову
+	// виводять з роботи і аварійні, і планові простої.
+	Availability float64 `json:"availability"`
+}
+
+// calculateSingleCircuit: ωос = Σωi, tв.ос = Σωi·tв,i / ωос,
+// kа.ос = ωос·tв.ос/8760, kп.ос = 1,2·max(μi·tп,i)/8760.
+func calculateSingleCircuit(elements []SystemElement) (SingleCircuit, error) {
+	var res SingleCircuit
+	if len(elements) == 0 {
+		return res, ValidationErrors{{Field: "elements", Message: "Схема не містить жодного елемента"}}
+	}
+
+	var errs ValidationErrors
+	for i, se := range elements {
+		e, ok := findElement(se.Type)
+		if !ok {
+			errs.add(fmt.Sprintf("elements[%d].type", i), "Рядок %d: елемент %q відсутній у каталозі", i+1, se.Type)
+			continue
+		}
+		errs.checkRange(fmt.Sprintf("elements[%d].quantity", i), fmt.Sprintf("Рядок %d (%s), кількість", i+1, e.Name), se.Quantity, 0, maxQuantity, true)
+	}
+	if len(errs) > 0 {
+		return res, errs
+	}
+
+	sumOmegaTv, maxPlanned := 0.0, 0.0
+	for _, se := range elements {
+		e, _ := findElement(se.Type)
+		line := ElementLine{Element: e, Quantity: se.Quantity}
+		line.Omega = e.Omega * se.Quantity
+		line.OmegaTv = line.Omega * e.Tv
+		line.Kp = e.Mu * e.Tp / hoursPerYear
+		res.Lines = append(res.Lines, line)
+
+		res.Omega += line.Omega
+		sumOmegaTv += line.OmegaTv
+		maxPlanned = math.Max(maxPlanned, e.Mu*e.Tp)
+	}
+
+	res.Tv = sumOmegaTv / res.Omega
+	res.Ka = res.Omega * res.Tv / hoursPerYear
+	res.Kp = 1.2 * maxPlanned / hoursPerYear
+	res.Availability = 1 - res.Ka - res.Kp
+	return res, nil
+}
+
+// Секційний вимикач двоколової схеми за замовчуванням.
+const defaultBreaker = "v10-oil"
+
+// DoubleCircuit — двоколова система з двох однакових кіл, з'єднаних
+// секційним вимикачем.
+type DoubleCircuit struct {
+	Breaker      ElementType `json:"breaker"`
+	OmegaCircuit float64     `json:"omegaCircuit"` // ωдк, 1/рік — одночасна відмова обох кіл
+	Omega        float64     `json:"omega"`        // ωдс = ωдк + ωсв, 1/рік
+	Ka           float64     `json:"ka"`           // ймовірність одночасного простою обох кіл
+	KaBreaker    float64     `json:"kaBreaker"`    // kа.св
+	Availability float64     `json:"availability"`
+}
+
+// calculateDoubleCircuit: ωдк = 2·ωос·(kа.ос + kп.ос), ωдс = ωдк + ωсв.
+// Обидва кола одночасно не працюють, коли відмовили обидва (kа.ос²) або
+// одне відмовило під час планового ремонту іншого (2·kа.ос·kп.ос).
+func calculateDoubleCircuit(single SingleCircuit, breakerID string) (DoubleCircuit, error) {
+	if breakerID == "" {
+		breakerID = defaultBreaker
+	}
+	b, ok := findElement(breakerID)
+	if !ok || !b.Breaker {
+		return DoubleCircuit{}, ValidationErrors{{Field: "breaker", Message: fmt.Sprintf("Секційний вимикач %q відсутній у каталозі", breakerID)}}
+	}
+
+	res := DoubleCircuit{Breaker: b}
+	res.OmegaCircuit = 2 * single.Omega * (single.Ka + single.Kp)
+	res.Omega = res.OmegaCircuit + b.Omega
+	res.Ka = single.Ka*single.Ka + 2*single.Ka*single.Kp
+	res.KaBreaker = b.Omega * b.Tv / hoursPerYear
+	res.Availability = 1 - res.Ka - res.KaBreaker
+	return res, nil
+}
+
+// Task1Result — порівняння одноколової та двоколової систем.
+type Task1Result struct {
+	Single SingleCircuit `json:"single"`
+	Double DoubleCircuit `json:"double"`
+}
+
+func calculateReliability(elements []SystemElement, breakerID string) (Task1Result, error) {
+	var res Task1Result
+	var err error
+	if res.Single, err = calculateSingleCircuit(elements); err != nil {
+		return res, err
+	}
+	res.Double, err = calculateDoubleCircuit(res.Single, breakerID)
+	return res, err
+}
+
+// Ratio — у скільки разів двоколова система відмовляє рідше.
+func (r Task1Result) Ratio() float64 {
+	return r.Single.Omega / r.Double.Omega
+}
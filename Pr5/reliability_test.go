@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// approx перевіряє відносне відхилення не більше tol.
+func approx(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.IsNaN(got) || math.Abs(got-want) > tol*math.Abs(want) {
+		t.Errorf("%s = %.6g, want %.6g (±%g%%)", name, got, want, tol*100)
+	}
+}
+
+// Приклад практичної роботи: В-110, ПЛ-110 10 км, Т-110, В-10, шини на 6 приєднань.
+func TestSingleCircuitExample(t *testing.T) {
+	res, err := calculateSingleCircuit(defaultSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx(t, "ωос", res.Omega, 0.295, 1e-9)
+	approx(t, "tв.ос", res.Tv, 10.7, 0.002)
+	approx(t, "kа.ос", res.Ka, 3.6e-4, 0.005)
+	approx(t, "kп.ос", res.Kp, 5.89e-3, 0.001)
+	approx(t, "A", res.Availability, 1-res.Ka-res.Kp, 1e-12)
+
+	lines := []struct {
+		id    string
+		omega float64
+	}{{"v110", 0.01}, {"pl110", 0.07}, {"t110", 0.015}, {"v10-oil", 0.02}, {"bus10", 0.18}}
+	for i, l := range lines {
+		if res.Lines[i].Element.ID != l.id {
+			t.Fatalf("рядок %d: %s, want %s", i+1, res.Lines[i].Element.ID, l.id)
+		}
+		approx(t, l.id+" ω·n", res.Lines[i].Omega, l.omega, 1e-9)
+	}
+}
+
+func TestSingleCircuitValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []SystemElement
+		field    string
+	}{
+		{"порожня схема", nil, "elements"},
+		{"невідомий елемент", []SystemElement{{Type: "x", Quantity: 1}}, "elements[0].type"},
+		{"нульова кількість", []SystemElement{{Type: "t110", Quantity: 0}}, "elements[0].quantity"},
+		{"від'ємна кількість", []SystemElement{{Type: "t110", Quantity: -1}}, "elements[0].quantity"},
+		{"NaN", []SystemElement{{Type: "v110", Quantity: 1}, {Type: "t110", Quantity: math.NaN()}}, "elements[1].quantity"},
+		{"Inf", []SystemElement{{Type: "t110", Quantity: math.Inf(1)}}, "elements[0].quantity"},
+		{"понад ліміт", []SystemElement{{Type: "pl110", Quantity: maxQuantity + 1}}, "elements[0].quantity"},
+	}
+	for _, tt := range tests {
+		_, err := calculateSingleCircuit(tt.elements)
+		if !errorList(err).has(tt.field) {
+			t.Errorf("%s: помилка %v, want поле %s", tt.name, err, tt.field)
+		}
+	}
+}
+
+func TestDoubleCircuitExample(t *testing.T) {
+	res, err := calculateReliability(defaultSystem, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := res.Double
+	if d.Breaker.ID != defaultBreaker {
+		t.Errorf("секційний вимикач %s, want %s", d.Breaker.ID, defaultBreaker)
+	}
+	approx(t, "ωдк", d.OmegaCircuit, 2*0.295*(res.Single.Ka+res.Single.Kp), 1e-12)
+	approx(t, "ωдк", d.OmegaCircuit, 3.69e-3, 0.002)
+	approx(t, "ωдс", d.Omega, 0.0237, 0.002)
+	approx(t, "kа.св", d.KaBreaker, 0.02*15/hoursPerYear, 1e-12)
+	approx(t, "ωос/ωдс", res.Ratio(), 12.45, 0.002)
+	if d.Availability <= res.Single.Availability {
+		t.Errorf("A двоколової %g не більше за одноколову %g", d.Availability, res.Single.Availability)
+	}
+}
+
+func TestDoubleCircuitBreaker(t *testing.T) {
+	single, err := calculateSingleCircuit(defaultSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := calculateDoubleCircuit(single, "v10-vacuum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx(t, "ωдс з вакуумним", res.Omega, res.OmegaCircuit+0.05, 1e-12)
+
+	for _, id := range []string{"t110", "немає"} {
+		if _, err := calculateDoubleCircuit(single, id); !errorList(err).has("breaker") {
+			t.Errorf("%s як секційний вимикач: помилка %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Перевірка вхідних даних. Помилки збираються всі одразу, щоб форма
+// показала їх разом, а API повернуло списком із назвами полів.
+
+// Межі правдоподібних значень.
+const (
+	maxQuantity = 1000 // шт, км або приєднань одного елемента
+	maxOmega    = 100  // 1/рік
+	maxPower    = 1e7  // кВт
+	maxSpecLoss = 1000 // грн/кВт·год
+	maxRepairCV = 10   // коефіцієнт варіації часу відновлення
+)
+
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // назва поля у JSON-запиті
+	Message string `json:"message"`
+}
+
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (v *ValidationErrors) add(field, format string, args ...any) {
+	*v = append(*v, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// fieldError — помилка одного поля.
+func fieldError(field, format string, args ...any) error {
+	var v ValidationErrors
+	v.add(field, format, args...)
+	return v
+}
+
+func (v ValidationErrors) has(field string) bool {
+	for _, e := range v {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// err повертає nil, якщо помилок немає.
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// errorList перетворює будь-яку помилку на список для шаблону.
+func errorList(err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+	var v ValidationErrors
+	if errors.As(err, &v) {
+		return v
+	}
+	return ValidationErrors{{Message: err.Error()}}
+}
+
+// checkRange перевіряє min ≤ x ≤ max; при positive значення має бути більшим за min.
+func (v *ValidationErrors) checkRange(field, label string, x, min, max float64, positive bool) {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, 0):
+		v.add(field, "%s: некоректне число", label)
+	case x < 0 && min >= 0:
+		v.add(field, "%s: значення не може бути від'ємним", label)
+	case positive && x <= min:
+		v.add(field, "%s: значення має бути більшим за %g", label, min)
+	case x < min:
+		v.add(field, "%s: значення має бути не меншим за %g", label, min)
+	case x > max:
+		v.add(field, "%s: значення %g неправдоподібно велике (більше %g)", label, x, max)
+	}
+}
+
+// parseFloat розбирає обов'язкове числове поле форми.
+func (v *ValidationErrors) parseFloat(field, label, value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		v.add(field, "%s: поле обов'язкове", label)
+		return 0
+	}
+	x, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		v.add(field, "%s: %q не є числом", label, value)
+		return 0
+	}
+	return x
+}
+
+// parseInt розбирає обов'язкове ціле поле форми.
+func (v *ValidationErrors) parseInt(field, label, value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		v.add(field, "%s: поле обов'язкове", label)
+		return 0
+	}
+	x, err := strconv.Atoi(value)
+	if err != nil {
+		v.add(field, "%s: %q не є цілим числом", label, value)
+		return 0
+	}
+	return x
+}
+
+// writeJSONError відповідає API-клієнту: {"error": "...", "errors": [...]}.
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	w.WriteHeader(status)
+	body := struct {
+		Error  string           `json:"error"`
+		Errors ValidationErrors `json:"errors,omitempty"`
+	}{Error: err.Error()}
+	var v ValidationErrors
+	if errors.As(err, &v) {
+		body.Errors = v
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// decodeJSON читає тіло API-запиту; невідомі поля — помилка.
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("некоректний JSON: %w", err)
+	}
+	return nil
+}
+
+// postOnly відхиляє інші методи: для API — JSON-помилкою, для форм — текстом.
+func postOnly(w http.ResponseWriter, r *http.Request, api bool) bool {
+	if r.Method == http.MethodPost {
+		return true
+	}
+	w.Header().Set("Allow", http.MethodPost)
+	if api {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writeJSONError(w, http.StatusMethodNotAllowed, errors.New("дозволено лише POST"))
+		return false
+	}
+	http.Error(w, "Дозволено лише POST: надішліть форму з головної сторінки", http.StatusMethodNotAllowed)
+	return false
+}
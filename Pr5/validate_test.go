@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckRange(t *testing.T) {
+	tests := []struct {
+		x        float64
+		positive bool
+		ok       bool
+	}{
+		{5, false, true},
+		{0, false, true},
+		{0, true, false},
+		{10, true, true},
+		{-1, false, false},
+		{11, false, false},
+		{math.NaN(), false, false},
+		{math.Inf(1), false, false},
+		{math.Inf(-1), false, false},
+	}
+	for _, tt := range tests {
+		var errs ValidationErrors
+		errs.checkRange("x", "X", tt.x, 0, 10, tt.positive)
+		if ok := len(errs) == 0; ok != tt.ok || (!ok && !errs.has("x")) {
+			t.Errorf("checkRange(%g, positive=%v): %v", tt.x, tt.positive, errs)
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	var errs ValidationErrors
+	if x := errs.parseFloat("a", "A", " 2,5 "); x != 2.5 || len(errs) > 0 {
+		t.Errorf("parseFloat(2,5) = %g, %v", x, errs)
+	}
+	if x := errs.parseInt("b", "B", "42"); x != 42 || len(errs) > 0 {
+		t.Errorf("parseInt(42) = %d, %v", x, errs)
+	}
+	errs.parseFloat("empty", "Порожнє", "  ")
+	errs.parseFloat("text", "Текст", "abc")
+	errs.parseInt("frac", "Дробове", "1.5")
+	for _, f := range []string{"empty", "text", "frac"} {
+		if !errs.has(f) {
+			t.Errorf("немає помилки для %s: %v", f, errs)
+		}
+	}
+
+	// NaN та Inf розбираються як числа і відхиляються перевіркою меж
+	for _, s := range []string{"NaN", "Inf", "-Inf"} {
+		var errs ValidationErrors
+		x := errs.parseFloat("x", "X", s)
+		errs.checkRange("x", "X", x, 0, 10, false)
+		if !errs.has("x") {
+			t.Errorf("%s прийнято", s)
+		}
+	}
+}
+
+func TestReduceFieldErrors(t *testing.T) {
+	el := Block{Type: "element", Element: "t110"}
+	tests := []struct {
+		block Block
+		field string
+	}{
+		{Block{Type: "series"}, "block.blocks"},
+		{Block{Type: "mesh", Blocks: []Block{el}}, "block.type"},
+		{Block{Type: "koutofn", K: 3, Blocks: []Block{el, el}}, "block.k"},
+		{Block{Type: "series", Blocks: []Block{el, {Type: "element", Element: "x"}}}, "block.blocks[1].element"},
+		{Block{Type: "parallel", Blocks: []Block{el, {Type: "element", Element: "t110", Quantity: -2}}}, "block.blocks[1].quantity"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.block.Reduce(); !errorList(err).has(tt.field) {
+			t.Errorf("%s: %v", tt.field, err)
+		}
+	}
+}
+
+func TestBlockFromRowsFieldErrors(t *testing.T) {
+	tests := []struct {
+		rows  []BlockRow
+		field string
+	}{
+		{nil, "rows"},
+		{[]BlockRow{{Level: "x", Type: "series"}}, "rows[0].level"},
+		{[]BlockRow{{Level: "1", Type: "series"}}, "rows[0].level"},
+		{[]BlockRow{{Level: "0", Type: "series"}, {Level: "2", Type: "element", Element: "t110"}}, "rows[1].level"},
+		{[]BlockRow{{Level: "0", Type: "series"}, {Level: "1", Type: "element", Element: "t110", Quantity: "два"}}, "rows[1].quantity"},
+		{[]BlockRow{{Level: "0", Type: "koutofn", K: "k"}}, "rows[0].k"},
+	}
+	for _, tt := range tests {
+		if _, err := blockFromRows(tt.rows); !errorList(err).has(tt.field) {
+			t.Errorf("%s: %v", tt.field, err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Графіки у форматі SVG. Погодинний: угорі — фактична потужність стовпцями
+// (зелені — в межах смуги, червоні — з небалансом) на тлі смуги допуску та
+// ступінчастої лінії заявленої потужності, внизу — прибуток інтервалу.
+
+const (
+	chartWidth  = 760
+	chartLeft   = 60
+	chartRight  = 20
+	chartPower  = 220 // висота панелі потужності
+	chartProfit = 140 // висота панелі прибутку
+	chartGap    = 50
+)
+
+func hourlyChart(res HourlyResult, tolerance float64) string {
+	n := len(res.Intervals)
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	step := plotWidth / float64(n)
+	x := func(i int) float64 { return chartLeft + float64(i)*step }
+
+	maxPower := 0.0
+	maxProfit := 0.0
+	for _, in := range res.Intervals {
+		_, upper := toleranceBand(in.Declared, tolerance)
+		maxPower = math.Max(maxPower, math.Max(upper, in.Actual))
+		maxProfit = math.Max(maxProfit, math.Abs(in.Profit))
+	}
+	if maxPower == 0 {
+		maxPower = 1
+	}
+	if maxProfit == 0 {
+		maxProfit = 1
+	}
+	powerTop := 20.0
+	py := func(p float64) float64 { return powerTop + chartPower*(1-p/maxPower) }
+	profitMid := powerTop + chartPower + chartGap + chartProfit/2
+	fy := func(v float64) float64 { return profitMid - v/maxProfit*chartProfit/2 }
+	height := profitMid + chartProfit/2 + 30
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%.0f" viewBox="0 0 %d %.0f" font-family="Arial, sans-serif" font-size="11">`, chartWidth, height, chartWidth, height)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>`)
+
+	// осі та підписи
+	fmt.Fprintf(&b, `<text x="%d" y="12">Потужність, МВт</text>`, chartLeft)
+	for _, frac := range []float64{0, 0.5, 1} {
+		y := py(maxPower * frac)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, chartLeft, y, chartWidth-chartRight, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%.2f</text>`, chartLeft-6, y+4, maxPower*frac)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f">Прибуток інтервалу, тис. грн</text>`, chartLeft, profitMid-chartProfit/2-8)
+	for _, v := range []float64{-maxProfit, 0, maxProfit} {
+		y := fy(v)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, chartLeft, y, chartWidth-chartRight, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%.1f</text>`, chartLeft-6, y+4, v)
+	}
+
+	// смуга допуску, фактична потужність і прибуток
+	for i, in := range res.Intervals {
+		lower, upper := toleranceBand(in.Declared, tolerance)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#cfe3ff"/>`, x(i), py(upper), step, py(lower)-py(upper))
+
+		color := "#2e9d4f"
+		if !in.Within {
+			color = "#c62828"
+		}
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" opacity="0.75"><title>%s: факт %.3f МВт, заявка %.3f МВт</title></rect>`,
+			x(i)+step*0.2, py(in.Actual), step*0.6, py(0)-py(in.Actual), color, in.Start, in.Actual, in.Declared)
+
+		top, bottom := fy(math.Max(in.Profit, 0)), fy(math.Min(in.Profit, 0))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %.2f тис. грн</title></rect>`,
+			x(i)+step*0.2, top, step*0.6, bottom-top, color, in.Start, in.Profit)
+	}
+
+	// заявлена потужність — ступінчаста лінія
+	var path strings.Builder
+	for i, in := range res.Intervals {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f,%.1f L%.1f,%.1f ", cmd, x(i), py(in.Declared), x(i+1), py(in.Declared))
+	}
+	fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#1a4fa0" stroke-width="2"/>`, strings.TrimSpace(path.String()))
+
+	// підписи годин
+	labelEvery := max(1, n/12)
+	for i := 0; i < n; i += labelEvery {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, x(i)+step/2, py(0)+14, res.Intervals[i].Start)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, x(i)+step/2, height-10, res.Intervals[i].Start)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// sweepChart — крива річного чистого ефекту (приріст прибутку мінус
+// вартість системи) від σ з позначками σ0, цільової σ та беззбитковості.
+func sweepChart(res SweepResult) string {
+	const height, top, bottom = 320.0, 20.0, 40.0
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := height - top - bottom
+
+	minSigma, maxSigma := res.Points[0].Sigma, res.Points[len(res.Points)-1].Sigma
+	if maxSigma == minSigma {
+		maxSigma = minSigma + 1
+	}
+	minNet, maxNet := 0.0, 0.0
+	for _, p := range res.Points {
+		minNet = math.Min(minNet, p.Net)
+		maxNet = math.Max(maxNet, p.Net)
+	}
+	if maxNet == minNet {
+		maxNet = minNet + 1
+	}
+	x := func(s float64) float64 { return chartLeft + (s-minSigma)/(maxSigma-minSigma)*plotWidth }
+	y := func(v float64) float64 { return top + (maxNet-v)/(maxNet-minNet)*plotHeight }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%.0f" viewBox="0 0 %d %.0f" font-family="Arial, sans-serif" font-size="11">`, chartWidth, height, chartWidth, height)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>`)
+	for _, v := range []float64{minNet, 0, maxNet} {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, chartLeft, y(v), chartWidth-chartRight, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%.0f</text>`, chartLeft-6, y(v)+4, v)
+	}
+	for i := 0; i <= 4; i++ {
+		s := minSigma + (maxSigma-minSigma)*float64(i)/4
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%.3g</text>`, x(s), height-bottom+16, s)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="12">Річний чистий ефект, тис. грн</text>`, chartLeft)
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">σ, МВт</text>`, chartWidth-chartRight, height-6)
+
+	marker := func(s float64, color, label string) {
+		if s < minSigma || s > maxSigma {
+			return
+		}
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="4 3"/>`, x(s), top, x(s), top+plotHeight, color)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`, x(s)+4, top+12, color, label)
+	}
+	marker(res.Current.Sigma, "#555", "σ0")
+	marker(res.Target.Sigma, "#1a4fa0", "ціль")
+	if res.HasBreakEven {
+		marker(res.BreakEven, "#c62828", "беззбитковість")
+	}
+
+	var path strings.Builder
+	for i, p := range res.Points {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f,%.1f ", cmd, x(p.Sigma), y(p.Net))
+	}
+	fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#2e9d4f" stroke-width="2"/>`, strings.TrimSpace(path.String()))
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// errorChart — гістограма похибок прогнозу (щільність) і крива
+// нормального розподілу N(bias, σ), підібраного за вибіркою.
+func errorChart(est ErrorEstimate) string {
+	const height, top, bottom = 280.0, 20.0, 40.0
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := height - top - bottom
+
+	// кількість інтервалів — за правилом Стерджеса
+	bins := min(40, int(math.Ceil(math.Log2(float64(est.N))))+1)
+	lo := math.Min(est.Errors[0], est.Bias-3*est.Sigma)
+	hi := math.Max(est.Errors[len(est.Errors)-1], est.Bias+3*est.Sigma)
+	width := (est.Errors[len(est.Errors)-1] - est.Errors[0]) / float64(bins)
+	if width == 0 {
+		width = est.Sigma
+	}
+	counts := make([]float64, bins)
+	for _, e := range est.Errors {
+		counts[min(bins-1, int((e-est.Errors[0])/width))]++
+	}
+	density := func(c float64) float64 { return c / (float64(est.N) * width) }
+	pdf := func(e float64) float64 {
+		z := (e - est.Bias) / est.Sigma
+		return math.Exp(-z*z/2) / (est.Sigma * math.Sqrt(2*math.Pi))
+	}
+	maxY := pdf(est.Bias)
+	for _, c := range counts {
+		maxY = math.Max(maxY, density(c))
+	}
+	x := func(e float64) float64 { return chartLeft + (e-lo)/(hi-lo)*plotWidth }
+	y := func(v float64) float64 { return top + plotHeight*(1-v/maxY) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%.0f" viewBox="0 0 %d %.0f" font-family="Arial, sans-serif" font-size="11">`, chartWidth, height, chartWidth, height)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>`)
+	fmt.Fprintf(&b, `<text x="%d" y="12">Щільність розподілу похибки</text>`, chartLeft)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#999"/>`, chartLeft, y(0), chartWidth-chartRight, y(0))
+	for i := 0; i <= 4; i++ {
+		e := lo + (hi-lo)*float64(i)/4
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%.2f</text>`, x(e), height-bottom+16, e)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">похибка Pфакт − Pпрогн, МВт</text>`, chartWidth-chartRight, height-6)
+
+	for i, c := range counts {
+		from := est.Errors[0] + float64(i)*width
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#9ec5f0" stroke="white"><title>%.3f … %.3f МВт: %.0f</title></rect>`,
+			x(from), y(density(c)), x(from+width)-x(from), y(0)-y(density(c)), from, from+width, c)
+	}
+
+	var path strings.Builder
+	const samples = 120
+	for i := 0; i <= samples; i++ {
+		e := lo + (hi-lo)*float64(i)/samples
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f,%.1f ", cmd, x(e), y(pdf(e)))
+	}
+	fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#c62828" stroke-width="2"/>`, strings.TrimSpace(path.String()))
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#555" stroke-dasharray="4 3"/>`, x(0), top, x(0), y(0))
+	b.WriteString(`</svg>`)
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Оцінка розподілу похибки прогнозу за історичними даними. Похибка —
+// e = Pфакт − Pпрогн, МВт; рядки, де прогноз і факт нульові (ніч), не
+// враховуються. Зсув (bias) — середнє e, σ — вибіркове стандартне
+// відхилення. Відповідність нормальному розподілу N(bias, σ) оцінюється
+// порівнянням квантилів, часток у смузі допуску та критерієм Лілієфорса
+// (Колмогорова — Смирнова з оціненими параметрами).
+
+const (
+	minHistory      = 10
+	lillieforsCoeff = 0.886 // критичне значення D ≈ 0,886/√n при α = 0,05
+)
+
+var estimateLevels = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99}
+
+// exampleHistory — прогноз і факт для станції на 5 МВт, МВт.
+const exampleHistory = `прогноз,факт
+4.8,5.1
+5.0,4.2
+5.2,5.9
+4.9,4.6
+5.1,5.3
+5.0,3.9
+4.7,5.0
+5.3,5.6
+5.0,5.05
+4.9,6.1
+5.1,4.4
+5.0,5.2
+4.8,4.7
+5.2,4.9
+5.0,5.8
+4.9,4.1
+5.1,5.15
+5.0,4.8
+4.8,5.5
+5.2,5.0
+5.0,4.5
+4.9,5.4
+5.1,6.0
+5.0,4.95
+4.8,4.3
+5.2,5.7
+5.0,5.1
+4.9,3.8
+5.1,5.0
+5.0,5.3`
+
+type QuantileRow struct {
+	Level     float64 `json:"level"`     // рівень p
+	Empirical float64 `json:"empirical"` // вибірковий квантиль похибки, МВт
+	Normal    float64 `json:"normal"`    // квантиль N(bias, σ), МВт
+}
+
+type ErrorEstimate struct {
+	N            int       `json:"n"`
+	Skipped      int       `json:"skipped"`      // нічні рядки
+	MeanForecast float64   `json:"meanForecast"` // середній прогноз — Pc для розрахунку прибутку, МВт
+	Bias         float64   `json:"bias"`         // МВт
+	Sigma        float64   `json:"sigma"`        // МВт
+	RMSE         float64   `json:"rmse"`         // МВт
+	Errors       []float64 `json:"-"`            // впорядковані похибки, МВт
+
+	Quantiles []QuantileRow `json:"quantiles"`
+
+	// частка інтервалів з |e| ≤ δ·Pпрогн: за вибіркою та за нормальною моделлю, %
+	ShareEmpirical float64 `json:"shareEmpirical"`
+	ShareNormal    float64 `json:"shareNormal"`
+
+	KS         float64 `json:"ks"` // статистика D
+	KSCritical float64 `json:"ksCritical"`
+	NormalFits bool    `json:"normalFits"` // D не перевищує критичного значення
+}
+
+// normalQuantile — квантиль нормального розподілу рівня p.
+func normalQuantile(p, mean, sigma float64) float64 {
+	return mean + sigma*math.Sqrt2*math.Erfinv(2*p-1)
+}
+
+// empiricalQuantile — квантиль упорядкованої вибірки з лінійною інтерполяцією.
+func empiricalQuantile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+func estimateErrors(forecast, actual []float64, tolerance float64) (ErrorEstimate, error) {
+	var est ErrorEstimate
+	within := 0
+	for i := range forecast {
+		if forecast[i] == 0 && actual[i] == 0 {
+			est.Skipped++
+			continue
+		}
+		e := actual[i] - forecast[i]
+		est.Errors = append(est.Errors, e)
+		est.MeanForecast += forecast[i]
+		est.Bias += e
+		est.RMSE += e * e
+		if lower, upper := toleranceBand(forecast[i], tolerance); actual[i] >= lower && actual[i] <= upper {
+			within++
+		}
+	}
+	est.N = len(est.Errors)
+	if est.N < minHistory {
+		return est, fmt.Errorf("для оцінки потрібно щонайменше %d значень, отримано %d", minHistory, est.N)
+	}
+	n := float64(est.N)
+	est.MeanForecast /= n
+	est.Bias /= n
+	est.RMSE = math.Sqrt(est.RMSE / n)
+	for _, e := range est.Errors {
+		est.Sigma += (e - est.Bias) * (e - est.Bias)
+	}
+	est.Sigma = math.Sqrt(est.Sigma / (n - 1))
+	if est.Sigma == 0 {
+		return est, fmt.Errorf("похибка прогнозу стала (%.3g МВт) — розподіл оцінити неможливо", est.Bias)
+	}
+	slices.Sort(est.Errors)
+
+	for _, p := range estimateLevels {
+		est.Quantiles = append(est.Quantiles, QuantileRow{
+			Level:     p,
+			Empirical: empiricalQuantile(est.Errors, p),
+			Normal:    normalQuantile(p, est.Bias, est.Sigma),
+		})
+	}
+
+	est.ShareEmpirical = float64(within) / n * 100
+	est.ShareNormal = getShareEnergy(est.MeanForecast, est.Bias, est.Sigma, tolerance)
+
+	for i, e := range est.Errors {
+		f := normalCDF(e, est.Bias, est.Sigma)
+		est.KS = math.Max(est.KS, math.Max(float64(i+1)/n-f, f-float64(i)/n))
+	}
+	est.KSCritical = lillieforsCoeff / math.Sqrt(n)
+	est.NormalFits = est.KS <= est.KSCritical
+	return est, nil
+}
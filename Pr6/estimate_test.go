@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEstimateExample(t *testing.T) {
+	forecast, actual, err := parsePairs(exampleHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	est, err := estimateErrors(forecast, actual, defaultTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.N != 30 || est.Skipped != 0 {
+		t.Errorf("N = %d, пропущено %d", est.N, est.Skipped)
+	}
+	approx(t, "зсув", est.Bias, 0.0117, 1e-4)
+	approx(t, "σ", est.Sigma, 0.5811, 1e-4)
+	approx(t, "D", est.KS, 0.0766, 1e-4)
+	approx(t, "Dкр", est.KSCritical, 0.886/math.Sqrt(30), 1e-12)
+	if !est.NormalFits {
+		t.Error("нормальна модель відхилена")
+	}
+	if !slices.IsSorted(est.Errors) {
+		t.Error("похибки не впорядковано")
+	}
+	for _, q := range est.Quantiles {
+		if q.Level == 0.5 {
+			approx(t, "медіана N(bias, σ)", q.Normal, est.Bias, 1e-12)
+		}
+	}
+}
+
+func TestEstimateTooShort(t *testing.T) {
+	// нічні рядки не враховуються: 9 денних значень із 12
+	forecast := []float64{0, 0, 0, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+	actual := []float64{0, 0, 0, 4, 5, 6, 4.5, 5.5, 5, 4.8, 5.2, 5.1}
+	est, err := estimateErrors(forecast, actual, defaultTolerance)
+	if err == nil {
+		t.Fatal("оцінку за 9 значеннями прийнято")
+	}
+	if est.N != minHistory-1 || est.Skipped != 3 {
+		t.Errorf("N = %d, пропущено %d", est.N, est.Skipped)
+	}
+
+	forecast = append(forecast, 5)
+	actual = append(actual, 5.3)
+	if _, err := estimateErrors(forecast, actual, defaultTolerance); err != nil {
+		t.Errorf("%d значень: %v", minHistory, err)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Погодинний розрахунок: замість середньої за добу потужності задаються
+// заявлена та фактична потужності для кожного з 24 годинних або 96
+// п'ятнадцятихвилинних інтервалів. Правила ті самі, що й для добового
+// розрахунку: енергія інтервалу, в якому фактична потужність не виходить
+// за смугу Pзаяв ± δ, продається за тарифом, енергія інтервалу з
+// профіцитом — за ціною профіциту, а за енергію інтервалу з дефіцитом
+// станція платить ціну дефіциту (знаки цін — див. market.go).
+
+// exampleProfile — ясна доба для станції на 5 МВт, МВт: заявлена, фактична.
+const exampleProfile = `заявлена,фактична
+0,0
+0,0
+0,0
+0,0
+0,0
+0.2,0.18
+0.9,0.95
+1.9,2.1
+2.9,2.7
+3.8,3.9
+4.5,4.6
+4.9,4.4
+5.0,5.1
+4.8,5.3
+4.3,4.2
+3.6,3.2
+2.7,2.75
+1.7,1.6
+0.8,0.85
+0.2,0.2
+0,0
+0,0
+0,0
+0,0`
+
+type HourlyInterval struct {
+	Start    string  // початок інтервалу, гг:хх
+	Declared float64 // заявлена потужність, МВт
+	Actual   float64 // фактична потужність, МВт
+
+	Energy    float64 // фактична енергія, МВт*год
+	Imbalance float64 // (Pфакт − Pзаяв)·Δt, МВт*год; додатний — профіцит
+	Within    bool    // відхилення в межах смуги
+	Revenue   float64 // надходження, тис. грн
+	Penalty   float64 // платежі станції, тис. грн
+	Profit    float64 // тис. грн
+}
+
+type HourlyResult struct {
+	Intervals []HourlyInterval
+	Step      float64 // тривалість інтервалу, год
+
+	Energy         float64 // МВт*год
+	EnergyDeclared float64 // МВт*год
+	EnergyWithin   float64 // енергія без небалансів, МВт*год
+	EnergyOutside  float64 // енергія з небалансами, МВт*год
+	Surplus        float64 // сумарний профіцит, МВт*год
+	Deficit        float64 // сумарний дефіцит, МВт*год
+	Share          float64 // частка енергії без небалансів, %
+	Settlement
+	Revenue float64 // надходження, тис. грн
+	Penalty float64 // платежі станції, тис. грн
+	Profit  float64 // тис. грн
+}
+
+// parseProfile розбирає пари «заявлена, фактична» по рядку на інтервал.
+func parseProfile(text string) (declared, actual []float64, err error) {
+	declared, actual, err = parsePairs(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n := len(declared); n != 24 && n != 96 {
+		return nil, nil, fmt.Errorf("потрібно 24 годинні або 96 п'ятнадцятихвилинних значень, отримано %d", n)
+	}
+	return declared, actual, nil
+}
+
+// parsePairs розбирає CSV з двома стовпцями потужностей, МВт. Роздільник —
+// кома, табуляція, пробіл або крапка з комою (тоді кома може бути
+// десятковою); рядок заголовка пропускається.
+func parsePairs(text string) (declared, actual []float64, err error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r", ""), "\n")
+	first := true
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		header := first
+		first = false
+		var fields []string
+		if strings.Contains(line, ";") {
+			fields = strings.Split(strings.ReplaceAll(line, ",", "."), ";")
+		} else {
+			fields = strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == '\t' || r == ' ' })
+		}
+		if len(fields) != 2 {
+			return nil, nil, fmt.Errorf("рядок %d: очікується два значення — прогнозна (заявлена) та фактична потужність", i+1)
+		}
+		d, err1 := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64)
+		a, err2 := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err1 != nil || err2 != nil {
+			if header {
+				continue
+			}
+			return nil, nil, fmt.Errorf("рядок %d: потужності мають бути числами", i+1)
+		}
+		if math.IsNaN(d) || math.IsNaN(a) || math.IsInf(d, 0) || math.IsInf(a, 0) {
+			return nil, nil, fmt.Errorf("рядок %d: потужність має бути скінченним числом", i+1)
+		}
+		if d < 0 || a < 0 {
+			return nil, nil, fmt.Errorf("рядок %d: потужність не може бути від'ємною", i+1)
+		}
+		declared = append(declared, d)
+		actual = append(actual, a)
+	}
+	return declared, actual, nil
+}
+
+func calculateHourly(declared, actual []float64, prices Prices, tolerance float64) HourlyResult {
+	res := HourlyResult{Step: 24 / float64(len(declared))}
+	for i := range declared {
+		minutes := int(math.Round(float64(i) * res.Step * 60))
+		in := HourlyInterval{
+			Start:    fmt.Sprintf("%02d:%02d", minutes/60, minutes%60),
+			Declared: declared[i],
+			Actual:   actual[i],
+		}
+		lower, upper := toleranceBand(in.Declared, tolerance)
+		in.Energy = in.Actual * res.Step
+		in.Imbalance = (in.Actual - in.Declared) * res.Step
+		in.Within = in.Actual >= lower && in.Actual <= upper
+		var st Settlement
+		switch {
+		case in.Within:
+			st = prices.settle(in.Energy, 0, 0)
+			res.EnergyWithin += in.Energy
+		case in.Actual > upper:
+			st = prices.settle(0, in.Energy, 0)
+			res.EnergyOutside += in.Energy
+		default:
+			st = prices.settle(0, 0, in.Energy)
+			res.EnergyOutside += in.Energy
+		}
+		in.Revenue, in.Penalty = st.income(), st.charges()
+		in.Profit = getMainProfit(in.Revenue, in.Penalty)
+		res.Sales += st.Sales
+		res.SurplusPay += st.SurplusPay
+		res.DeficitPay += st.DeficitPay
+
+		res.Energy += in.Energy
+		res.EnergyDeclared += in.Declared * res.Step
+		if in.Imbalance > 0 {
+			res.Surplus += in.Imbalance
+		} else {
+			res.Deficit -= in.Imbalance
+		}
+		res.Revenue += in.Revenue
+		res.Penalty += in.Penalty
+		res.Intervals = append(res.Intervals, in)
+	}
+	if res.Energy > 0 {
+		res.Share = res.EnergyWithin / res.Energy * 100
+	}
+	res.Profit = getMainProfit(res.Revenue, res.Penalty)
+	return res
+}
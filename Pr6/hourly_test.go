@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+// Доба з трьома ненульовими годинами: 10:00 у смузі, 11:00 профіцит, 12:00 дефіцит.
+func TestCalculateHourly(t *testing.T) {
+	declared := make([]float64, 24)
+	actual := make([]float64, 24)
+	declared[10], actual[10] = 4, 4.1
+	declared[11], actual[11] = 4, 5
+	declared[12], actual[12] = 4, 3
+
+	res := calculateHourly(declared, actual, Prices{Sale: 7, Surplus: 2, Deficit: 9}, 5)
+	if len(res.Intervals) != 24 || res.Step != 1 || res.Intervals[11].Start != "11:00" {
+		t.Fatalf("інтервали: %d по %g год, 11-й з %s", len(res.Intervals), res.Step, res.Intervals[11].Start)
+	}
+	if !res.Intervals[10].Within || res.Intervals[11].Within || res.Intervals[12].Within {
+		t.Error("неправильно визначено інтервали в смузі")
+	}
+	approx(t, "W", res.Energy, 12.1, 1e-9)
+	approx(t, "W заяв", res.EnergyDeclared, 12, 1e-9)
+	approx(t, "W у смузі", res.EnergyWithin, 4.1, 1e-9)
+	approx(t, "W поза смугою", res.EnergyOutside, 8, 1e-9)
+	approx(t, "профіцит", res.Surplus, 1.1, 1e-9)
+	approx(t, "дефіцит", res.Deficit, 1, 1e-9)
+	approx(t, "частка", res.Share, 4.1/12.1*100, 1e-9)
+	approx(t, "продаж", res.Sales, 28.7, 1e-9)
+	approx(t, "оплата профіциту", res.SurplusPay, 10, 1e-9)
+	approx(t, "оплата дефіциту", res.DeficitPay, 27, 1e-9)
+	approx(t, "надходження", res.Revenue, 38.7, 1e-9)
+	approx(t, "платежі", res.Penalty, 27, 1e-9)
+	approx(t, "прибуток", res.Profit, 11.7, 1e-9)
+
+	// за замовчуванням за профіцит станція платить тариф
+	res = calculateHourly(declared, actual, Prices{Sale: 7, Surplus: -7, Deficit: 7}, 5)
+	approx(t, "надходження (за замовчуванням)", res.Revenue, 28.7, 1e-9)
+	approx(t, "платежі (за замовчуванням)", res.Penalty, 56, 1e-9)
+	approx(t, "прибуток (за замовчуванням)", res.Profit, -27.3, 1e-9)
+}
+
+func TestHourlyExample(t *testing.T) {
+	declared, actual, err := parseProfile(exampleProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := calculateHourly(declared, actual, Prices{Sale: 7, Surplus: -7, Deficit: 7}, defaultTolerance)
+	approx(t, "прибуток", res.Profit, -3.71, 0.005)
+	res = calculateHourly(declared, actual, Prices{Sale: 7, Surplus: 2, Deficit: 9}, defaultTolerance)
+	approx(t, "прибуток (2/9)", res.Profit, 54.93, 0.005)
+
+	if _, _, err := parseProfile("1,1\n2,2"); err == nil {
+		t.Error("профіль із двох інтервалів прийнято")
+	}
+}
@@ -1,36 +1,78 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type PageData struct {
 	AverageDailyCapacity string
 	MeanSquareDeviation  string
 	Oversight            string
-	CostElectricity      string
+	Prices               PriceForm
+	Tolerance            string
+
+	HasResult  bool
+	Current    DailyResult
+	Improved   DailyResult
+	MainProfit float64
+	LowerBound float64
+	UpperBound float64
+	Error      string
+
+	SigmaFrom   string
+	SigmaTo     string
+	AnnualCost  string
+	DaysPerYear string
+	Sweep       *SweepResult
+	SweepChart  template.HTML
+
+	PlantRows          []PlantRow
+	Correlation        string
+	PortfolioTolerance string
+	PortfolioPrices    PriceForm
+	DaysPerMonth       string
+	Portfolio          *PortfolioResult
+	PortfolioError     string
+
+	History           string
+	EstimateTolerance string
+	EstimatePrices    PriceForm
+	Estimate          *ErrorEstimate
+	EstimateDaily     *DailyResult // з оціненими зсувом і σ
+	EstimateUnbiased  *DailyResult // та сама σ без зсуву
+	EstimateChart     template.HTML
+	EstimateError     string
+
+	HourlyProfile   string
+	HourlyPrices    PriceForm
+	HourlyTolerance string
+	Hourly          *HourlyResult
+	HourlyChart     template.HTML
+	HourlyError     string
+}
+
+// PlantRow — рядок форми портфеля, значення як введено.
+type PlantRow struct {
+	Name, Capacity, Sigma, Tariff string
+}
 
-	HasResult bool
+// emptyPlantRows — скільки порожніх рядків додається для нових станцій.
+const emptyPlantRows = 2
 
-	ShareEnergy         int
-	W1                  float64
-	Profit1             float64
-	W2                  float64
-	Fine1               float64
-	ImprovedShareEnergy int
-	W3                  float64
-	Profit2             float64
-	W4                  float64
-	Fine2               float64
-	MainProfit          float64
-	Error               string
+var templateFuncs = template.FuncMap{
+	"isInf": func(v float64) bool { return math.IsInf(v, 0) },
+	"sub":   func(a, b float64) float64 { return a - b },
 }
 
-var pageTmpl = template.Must(template.New("page").Parse(`
+var pageTmpl = template.Must(template.New("page").Funcs(templateFuncs).Parse(`
 <!DOCTYPE html>
 <html lang="uk">
 <head>
@@ -59,6 +101,12 @@ var pageTmpl = template.Must(template.New("page").Parse(`
 			grid-template-columns: 1fr;
 			gap: 10px;
 		}
+		fieldset{
+			display: grid;
+			gap: 10px;
+			border: 1px solid #ccc;
+			border-radius: 6px;
+		}
 		input{
 			padding: 8px;
 			font-size: 16px;
@@ -72,6 +120,22 @@ var pageTmpl = template.Must(template.New("page").Parse(`
 		p{
 			font-size: 16px;
 		}
+		.result-section table{
+			border-collapse: collapse;
+			margin-top: 10px;
+		}
+		.result-section td, .result-section th{
+			border: 1px solid #ccc;
+			padding: 4px 8px;
+			text-align: right;
+		}
+		.outside td{
+			background: #fde7e7;
+		}
+		textarea{
+			font-family: monospace;
+			font-size: 14px;
+		}
 		.error{
 			color: #b00020;
 			font-weight: bold;
@@ -98,36 +162,266 @@ var pageTmpl = template.Must(template.New("page").Parse(`
 			<label for="oversight">Зменшити похибку до:</label>
 			<input type="number" step="any" id="oversight" name="oversight" placeholder="0.25" value="{{.Oversight}}">
 
-			<label for="cost-electricity">В, грн/кВт*год:</label>
-			<input type="number" step="any" id="cost-electricity" name="cost-electricity" placeholder="7" value="{{.CostElectricity}}">
+			<label for="cost-electricity">Тариф продажу В, грн/кВт*год:</label>
+			<input type="number" step="any" id="cost-electricity" name="cost-electricity" placeholder="7" value="{{.Prices.Sale}}">
+
+			<label for="price-surplus">Ціна позитивного небалансу (профіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="price-surplus" name="price-surplus" placeholder="мінус тариф" value="{{.Prices.Surplus}}">
+
+			<label for="price-deficit">Ціна негативного небалансу (дефіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="price-deficit" name="price-deficit" placeholder="як тариф" value="{{.Prices.Deficit}}">
+
+			<label for="tolerance">Допустиме відхилення від P<sub>c</sub> без штрафу, %:</label>
+			<input type="number" step="any" id="tolerance" name="tolerance" placeholder="5" value="{{.Tolerance}}">
 
 			<button type="submit">Submit</button>
+
+			<fieldset>
+				<legend>Окупність системи прогнозування</legend>
+
+				<label for="sigma-from">σ від, МВт:</label>
+				<input type="number" step="any" id="sigma-from" name="sigma-from" placeholder="0.05" value="{{.SigmaFrom}}">
+
+				<label for="sigma-to">σ до, МВт:</label>
+				<input type="number" step="any" id="sigma-to" name="sigma-to" placeholder="як поточне" value="{{.SigmaTo}}">
+
+				<label for="annual-cost">Річна вартість системи прогнозування, тис. грн:</label>
+				<input type="number" step="any" id="annual-cost" name="annual-cost" placeholder="50000" value="{{.AnnualCost}}">
+
+				<label for="days-per-year">Днів роботи на рік:</label>
+				<input type="number" step="any" id="days-per-year" name="days-per-year" placeholder="365" value="{{.DaysPerYear}}">
+
+				<button type="submit" formaction="/sweep">Побудувати залежність від σ</button>
+			</fieldset>
 		</form>
 	</section>
 
 	{{if .HasResult}}
 	<section class="result-section">
 		<div>
-			<p><span>Частка енергії, що генерується без небалансів: </span><span>{{.ShareEnergy}}</span><span> %</span></p>
-			<p><span>W1: </span><span>{{printf "%.2f" .W1}}</span><span> МВт*год</span></p>
-			<p><span>Прибуток 1: </span><span>{{printf "%.2f" .Profit1}}</span><span> тис. грн</span></p>
-			<p><span>W2: </span><span>{{printf "%.2f" .W2}}</span><span> МВт*год</span></p>
-			<p><span>Штраф 1: </span><span>{{printf "%.2f" .Fine1}}</span><span> тис. грн</span></p>
-			<p><span>Після вдосконалення системи прогнозу частка енергії, що генерується без небалансів: </span><span>{{.ImprovedShareEnergy}}</span><span> %</span></p>
-			<p><span>W3: </span><span>{{printf "%.2f" .W3}}</span><span> МВт*год</span></p>
-			<p><span>Прибуток 2: </span><span>{{printf "%.1f" .Profit2}}</span><span> тис. грн</span></p>
-			<p><span>W4: </span><span>{{printf "%.2f" .W4}}</span><span> МВт*год</span></p>
-			<p><span>Штраф 2: </span><span>{{printf "%.2f" .Fine2}}</span><span> тис. грн</span></p>
+			<p><span>Смуга без небалансів: </span><span>{{printf "%.3f" .LowerBound}} – {{printf "%.3f" .UpperBound}}</span><span> МВт</span></p>
+			{{with .Current}}
+			<p><span>Частка енергії, що генерується без небалансів: </span><span>{{printf "%.2f" .Share}}</span><span> % (профіцит {{printf "%.2f" .SurplusShare}} %, дефіцит {{printf "%.2f" .DeficitShare}} %)</span></p>
+			<p><span>W1: </span><span>{{printf "%.2f" .Within}}</span><span> МВт*год</span></p>
+			<p><span>Прибуток 1: </span><span>{{printf "%.2f" .Revenue}}</span><span> тис. грн</span></p>
+			<p><span>W2: </span><span>{{printf "%.2f" .Outside}}</span><span> МВт*год (профіцит {{printf "%.2f" .Surplus}}, дефіцит {{printf "%.2f" .Deficit}})</span></p>
+			<p><span>Штраф 1: </span><span>{{printf "%.2f" .Fine}}</span><span> тис. грн</span></p>
+			{{template "settlement" .Settlement}}
+			{{end}}
+			{{with .Improved}}
+			<p><span>Після вдосконалення системи прогнозу частка енергії, що генерується без небалансів: </span><span>{{printf "%.2f" .Share}}</span><span> % (профіцит {{printf "%.2f" .SurplusShare}} %, дефіцит {{printf "%.2f" .DeficitShare}} %)</span></p>
+			<p><span>W3: </span><span>{{printf "%.2f" .Within}}</span><span> МВт*год</span></p>
+			<p><span>Прибуток 2: </span><span>{{printf "%.1f" .Revenue}}</span><span> тис. грн</span></p>
+			<p><span>W4: </span><span>{{printf "%.2f" .Outside}}</span><span> МВт*год (профіцит {{printf "%.2f" .Surplus}}, дефіцит {{printf "%.2f" .Deficit}})</span></p>
+			<p><span>Штраф 2: </span><span>{{printf "%.2f" .Fine}}</span><span> тис. грн</span></p>
+			{{template "settlement" .Settlement}}
+			{{end}}
 			<p><span>Головний прибуток: </span><span>{{printf "%.1f" .MainProfit}}</span><span> тис. грн</span></p>
 		</div>
 	</section>
 	{{end}}
+
+	{{with .Sweep}}
+	<section class="result-section">
+		<p><span>Добовий прибуток за поточної σ = {{printf "%.3g" .Current.Sigma}} МВт: </span><span>{{printf "%.2f" .Current.Profit}}</span><span> тис. грн</span></p>
+		{{if .HasBreakEven}}
+		<p><span>σ беззбитковості: </span><span>{{printf "%.4f" .BreakEven}}</span><span> МВт — система окупається, якщо знизити похибку принаймні до цього значення</span></p>
+		{{else}}
+		<p class="error">На розрахованому відрізку σ точки беззбитковості немає: річний приріст прибутку ніде не переходить через вартість системи {{printf "%.0f" .AnnualCost}} тис. грн на рік.</p>
+		{{end}}
+		{{with .Target}}
+		<p><span>Для цільової σ = {{printf "%.3g" .Sigma}} МВт: річний приріст </span><span>{{printf "%.0f" .Gain}}</span><span> тис. грн, чистий ефект {{printf "%.0f" .Net}} тис. грн, окупність {{if isInf .Payback}}—{{else}}{{printf "%.1f" .Payback}} днів{{end}}</span></p>
+		{{end}}
+
+		{{$.SweepChart}}
+
+		<table>
+			<tr><th>σ, МВт</th><th>Частка без небалансів, %</th><th>Прибуток за добу, тис. грн</th><th>Приріст за рік, тис. грн</th><th>Чистий ефект, тис. грн</th><th>Окупність, днів</th></tr>
+			{{range .Points}}
+			<tr{{if lt .Net 0.0}} class="outside"{{end}}><td>{{printf "%.3f" .Sigma}}</td><td>{{printf "%.2f" .Share}}</td><td>{{printf "%.2f" .Profit}}</td><td>{{printf "%.0f" .Gain}}</td><td>{{printf "%.0f" .Net}}</td><td>{{if isInf .Payback}}—{{else}}{{printf "%.1f" .Payback}}{{end}}</td></tr>
+			{{end}}
+		</table>
+	</section>
+	{{end}}
+
+	<section class="form-section">
+		<h2>Портфель сонячних електростанцій:</h2>
+
+		{{if .PortfolioError}}
+			<p class="error">{{.PortfolioError}}</p>
+		{{end}}
+
+		<form method="POST" action="/portfolio">
+			<table>
+				<tr><th>Станція</th><th>P<sub>c</sub>, МВт</th><th>σ, МВт</th><th>Тариф, грн/кВт*год</th></tr>
+				{{range .PlantRows}}
+				<tr>
+					<td><input type="text" name="plant-name" value="{{.Name}}"></td>
+					<td><input type="number" step="any" name="plant-capacity" value="{{.Capacity}}"></td>
+					<td><input type="number" step="any" name="plant-sigma" value="{{.Sigma}}"></td>
+					<td><input type="number" step="any" name="plant-tariff" value="{{.Tariff}}"></td>
+				</tr>
+				{{end}}
+			</table>
+
+			<label for="correlation">Кореляція похибок прогнозу між станціями ρ:</label>
+			<input type="number" step="any" id="correlation" name="correlation" placeholder="0.5" value="{{.Correlation}}">
+
+			<label for="portfolio-tolerance">Допустиме відхилення без штрафу, %:</label>
+			<input type="number" step="any" id="portfolio-tolerance" name="tolerance" placeholder="5" value="{{.PortfolioTolerance}}">
+
+			<label for="portfolio-price-surplus">Ціна позитивного небалансу (профіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="portfolio-price-surplus" name="price-surplus" placeholder="мінус тариф станції" value="{{.PortfolioPrices.Surplus}}">
+
+			<label for="portfolio-price-deficit">Ціна негативного небалансу (дефіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="portfolio-price-deficit" name="price-deficit" placeholder="як тариф станції" value="{{.PortfolioPrices.Deficit}}">
+
+			<label for="days-per-month">Днів у місяці:</label>
+			<input type="number" step="any" id="days-per-month" name="days-per-month" placeholder="30" value="{{.DaysPerMonth}}">
+
+			<button type="submit">Розрахувати портфель</button>
+		</form>
+	</section>
+
+	{{with .Portfolio}}
+	<section class="result-section">
+		<table>
+			<tr><th rowspan="2">Станція</th><th rowspan="2">Частка без небалансів, %</th><th colspan="3">За добу, тис. грн</th><th colspan="3">За місяць, тис. грн</th></tr>
+			<tr><th>Виручка</th><th>Штраф</th><th>Прибуток</th><th>Виручка</th><th>Штраф</th><th>Прибуток</th></tr>
+			{{range .Plants}}
+			<tr><td>{{.Name}}</td><td>{{printf "%.2f" .Daily.Share}}</td>{{template "totals" .Daily.Totals}}{{template "totals" .Month}}</tr>
+			{{end}}
+			<tr><th>Разом окремо</th><td></td>{{template "totals" .Standalone}}{{template "totals" .StandaloneMonth}}</tr>
+			<tr><th>Балансуюча група</th><td>{{printf "%.2f" .Aggregate.Share}}</td>{{template "totals" .Aggregate.Totals}}{{template "totals" .AggregateMonth}}</tr>
+		</table>
+		<p><span>Сумарна потужність: </span><span>{{printf "%.2f" .Capacity}}</span><span> МВт</span></p>
+		<p><span>σ портфеля при ρ = {{printf "%.2f" .Correlation}}: </span><span>{{printf "%.3f" .Sigma}}</span><span> МВт (Σσ = {{printf "%.3f" .SigmaSum}} МВт)</span></p>
+		<p><span>Ефект об'єднання в балансуючу групу: </span><span>{{printf "%.2f" .Benefit}}</span><span> тис. грн за добу, {{printf "%.1f" .BenefitMonth}} тис. грн за місяць ({{printf "%g" .Days}} днів)</span></p>
+	</section>
+	{{end}}
+
+	<section class="form-section">
+		<h2>Погодинний розрахунок за заявленим і фактичним графіками генерації:</h2>
+
+		{{if .HourlyError}}
+			<p class="error">{{.HourlyError}}</p>
+		{{end}}
+
+		<form method="POST" action="/hourly" enctype="multipart/form-data">
+			<label for="profile">24 або 96 рядків «заявлена, фактична» потужність, МВт:</label>
+			<textarea id="profile" name="profile" rows="12">{{.HourlyProfile}}</textarea>
+
+			<label for="profile-file">або файл CSV:</label>
+			<input type="file" id="profile-file" name="profile-file" accept=".csv,.txt">
+
+			<label for="hourly-cost-electricity">Тариф продажу В, грн/кВт*год:</label>
+			<input type="number" step="any" id="hourly-cost-electricity" name="cost-electricity" placeholder="7" value="{{.HourlyPrices.Sale}}">
+
+			<label for="hourly-price-surplus">Ціна позитивного небалансу (профіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="hourly-price-surplus" name="price-surplus" placeholder="мінус тариф" value="{{.HourlyPrices.Surplus}}">
+
+			<label for="hourly-price-deficit">Ціна негативного небалансу (дефіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="hourly-price-deficit" name="price-deficit" placeholder="як тариф" value="{{.HourlyPrices.Deficit}}">
+
+			<label for="hourly-tolerance">Допустиме відхилення від заявки без штрафу, %:</label>
+			<input type="number" step="any" id="hourly-tolerance" name="tolerance" placeholder="5" value="{{.HourlyTolerance}}">
+
+			<button type="submit">Розрахувати за інтервалами</button>
+		</form>
+	</section>
+
+	{{with .Hourly}}
+	<section class="result-section">
+		<p><span>Вироблено: </span><span>{{printf "%.2f" .Energy}}</span><span> МВт*год (заявлено {{printf "%.2f" .EnergyDeclared}} МВт*год)</span></p>
+		<p><span>Енергія без небалансів: </span><span>{{printf "%.2f" .EnergyWithin}}</span><span> МВт*год ({{printf "%.2f" .Share}} %)</span></p>
+		<p><span>Енергія з небалансами: </span><span>{{printf "%.2f" .EnergyOutside}}</span><span> МВт*год</span></p>
+		<p><span>Профіцит / дефіцит: </span><span>{{printf "%.2f" .Surplus}} / {{printf "%.2f" .Deficit}}</span><span> МВт*год</span></p>
+		<p><span>Виручка: </span><span>{{printf "%.2f" .Revenue}}</span><span> тис. грн</span></p>
+		<p><span>Штраф: </span><span>{{printf "%.2f" .Penalty}}</span><span> тис. грн</span></p>
+		{{template "settlement" .Settlement}}
+		<p><span>Чистий прибуток за добу: </span><span>{{printf "%.2f" .Profit}}</span><span> тис. грн</span></p>
+
+		{{$.HourlyChart}}
+
+		<table>
+			<tr><th>Інтервал</th><th>Заявлено, МВт</th><th>Факт, МВт</th><th>Небаланс, МВт*год</th><th>Виручка, тис. грн</th><th>Штраф, тис. грн</th><th>Прибуток, тис. грн</th></tr>
+			{{range .Intervals}}
+			<tr{{if not .Within}} class="outside"{{end}}><td>{{.Start}}</td><td>{{printf "%.3f" .Declared}}</td><td>{{printf "%.3f" .Actual}}</td><td>{{printf "%.3f" .Imbalance}}</td><td>{{printf "%.2f" .Revenue}}</td><td>{{printf "%.2f" .Penalty}}</td><td>{{printf "%.2f" .Profit}}</td></tr>
+			{{end}}
+		</table>
+	</section>
+	{{end}}
+
+	<section class="form-section">
+		<h2>Оцінка похибки прогнозу за історичними даними:</h2>
+
+		{{if .EstimateError}}
+			<p class="error">{{.EstimateError}}</p>
+		{{end}}
+
+		<form method="POST" action="/estimate" enctype="multipart/form-data">
+			<label for="history">Рядки «прогнозна, фактична» потужність, МВт:</label>
+			<textarea id="history" name="history" rows="12">{{.History}}</textarea>
+
+			<label for="history-file">або файл CSV:</label>
+			<input type="file" id="history-file" name="history-file" accept=".csv,.txt">
+
+			<label for="estimate-cost-electricity">Тариф продажу В, грн/кВт*год:</label>
+			<input type="number" step="any" id="estimate-cost-electricity" name="cost-electricity" placeholder="7" value="{{.EstimatePrices.Sale}}">
+
+			<label for="estimate-price-surplus">Ціна позитивного небалансу (профіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="estimate-price-surplus" name="price-surplus" placeholder="мінус тариф" value="{{.EstimatePrices.Surplus}}">
+
+			<label for="estimate-price-deficit">Ціна негативного небалансу (дефіцит), грн/кВт*год:</label>
+			<input type="number" step="any" id="estimate-price-deficit" name="price-deficit" placeholder="як тариф" value="{{.EstimatePrices.Deficit}}">
+
+			<label for="estimate-tolerance">Допустиме відхилення від прогнозу без штрафу, %:</label>
+			<input type="number" step="any" id="estimate-tolerance" name="tolerance" placeholder="5" value="{{.EstimateTolerance}}">
+
+			<button type="submit">Оцінити похибку та розрахувати прибуток</button>
+		</form>
+	</section>
+
+	{{with .Estimate}}
+	<section class="result-section">
+		<p><span>Використано значень: </span><span>{{.N}}</span><span>{{if .Skipped}} (пропущено нічних: {{.Skipped}}){{end}}</span></p>
+		<p><span>Середній прогноз P<sub>c</sub>: </span><span>{{printf "%.3f" .MeanForecast}}</span><span> МВт</span></p>
+		<p><span>Систематична похибка (зсув): </span><span>{{printf "%.4f" .Bias}}</span><span> МВт</span></p>
+		<p><span>Сер. квад. відхилення σ: </span><span>{{printf "%.4f" .Sigma}}</span><span> МВт (RMSE {{printf "%.4f" .RMSE}} МВт)</span></p>
+		<p><span>Частка в межах допуску: </span><span>{{printf "%.2f" .ShareEmpirical}} %</span><span> за даними, {{printf "%.2f" .ShareNormal}} % за нормальною моделлю</span></p>
+		<p{{if not .NormalFits}} class="error"{{end}}><span>Критерій Лілієфорса: </span><span>D = {{printf "%.4f" .KS}}</span><span>, критичне {{printf "%.4f" .KSCritical}} — {{if .NormalFits}}нормальний розподіл узгоджується з даними{{else}}нормальний розподіл не узгоджується з даними, розрахунок прибутку наближений{{end}}</span></p>
+
+		{{$.EstimateChart}}
+
+		<table>
+			<tr><th>p</th><th>Квантиль за даними, МВт</th><th>Квантиль N(зсув, σ), МВт</th><th>Різниця, МВт</th></tr>
+			{{range .Quantiles}}
+			<tr><td>{{printf "%g" .Level}}</td><td>{{printf "%.4f" .Empirical}}</td><td>{{printf "%.4f" .Normal}}</td><td>{{printf "%.4f" (sub .Empirical .Normal)}}</td></tr>
+			{{end}}
+		</table>
+
+		{{with $.EstimateDaily}}
+		<p><span>Прибуток за добу з оціненими зсувом і σ: </span><span>{{printf "%.2f" .Profit}}</span><span> тис. грн (виручка {{printf "%.2f" .Revenue}}, штраф {{printf "%.2f" .Fine}})</span></p>
+		{{end}}
+		{{with $.EstimateUnbiased}}
+		<p><span>Якщо усунути зсув прогнозу: </span><span>{{printf "%.2f" .Profit}}</span><span> тис. грн за добу</span></p>
+		{{end}}
+		<p>Оцінені P<sub>c</sub> та σ підставлено у форму розрахунку прибутку вгорі сторінки.</p>
+	</section>
+	{{end}}
 </body>
 </html>
+{{define "settlement"}}<p><span>Розрахунок за небаланси (+ надходження, − платіж станції): </span><span>профіцит {{printf "%+.2f" .SurplusPay}}, дефіцит {{printf "%+.2f" (sub 0 .DeficitPay)}}</span><span> тис. грн</span></p>{{end}}
+{{define "totals"}}<td>{{printf "%.2f" .Revenue}}</td><td>{{printf "%.2f" .Fine}}</td><td>{{printf "%.2f" .Profit}}</td>{{end}}
 `))
 
 func main() {
 	http.HandleFunc("/", homePage)
+	http.HandleFunc("/hourly", hourlyPage)
+	http.HandleFunc("/sweep", sweepPage)
+	http.HandleFunc("/portfolio", portfolioPage)
+	http.HandleFunc("/estimate", estimatePage)
+	http.HandleFunc("/api/estimate", apiEstimate)
 
 	fmt.Println("Server started at http://localhost:8080")
 	err := http.ListenAndServe(":8080", nil)
@@ -136,99 +430,438 @@ func main() {
 	}
 }
 
+// render заповнює поля погодинної форми прикладом і виводить сторінку.
+func render(w http.ResponseWriter, data PageData) {
+	if data.HourlyProfile == "" {
+		data.HourlyProfile = exampleProfile
+	}
+	if data.History == "" {
+		data.History = exampleHistory
+	}
+	if data.EstimateTolerance == "" {
+		data.EstimateTolerance = formatFloat(defaultTolerance)
+	}
+	if data.HourlyTolerance == "" {
+		data.HourlyTolerance = formatFloat(defaultTolerance)
+	}
+
+	if data.PlantRows == nil {
+		for _, p := range examplePlants {
+			data.PlantRows = append(data.PlantRows, PlantRow{p.Name, formatFloat(p.Capacity), formatFloat(p.Sigma), formatFloat(p.Tariff)})
+		}
+		data.Correlation = formatFloat(exampleCorrelation)
+	}
+	for i := 0; i < emptyPlantRows; i++ {
+		data.PlantRows = append(data.PlantRows, PlantRow{})
+	}
+	if data.PortfolioTolerance == "" {
+		data.PortfolioTolerance = formatFloat(defaultTolerance)
+	}
+
+	err := pageTmpl.Execute(w, data)
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+	}
+}
+
 func homePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
 	data := PageData{}
 
 	if r.Method == http.MethodPost {
-		data.AverageDailyCapacity = r.FormValue("average-daily-capacity")
-		data.MeanSquareDeviation = r.FormValue("mean-square-deviation")
-		data.Oversight = r.FormValue("oversight")
-		data.CostElectricity = r.FormValue("cost-electricity")
-
-		averageCapacity, err1 := strconv.ParseFloat(data.AverageDailyCapacity, 64)
-		meanSquareDev, err2 := strconv.ParseFloat(data.MeanSquareDeviation, 64)
-		oversight, err3 := strconv.ParseFloat(data.Oversight, 64)
-		costElectricity, err4 := strconv.ParseFloat(data.CostElectricity, 64)
-
-		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
-			data.Error = "Будь ласка, введіть коректні числові значення."
-		} else {
-			calcShareEnergy := getShareEnergy(averageCapacity, meanSquareDev)
-			calcW1 := getW1(averageCapacity, calcShareEnergy)
-			calcProfit1 := getProfit(calcW1, costElectricity)
-			calcW2 := getW2(averageCapacity, calcShareEnergy)
-			calcFine1 := getFine(calcW2, costElectricity)
-
-			calcImprovedShareEnergy := getShareEnergy(averageCapacity, oversight)
-			calcW3 := getW1(averageCapacity, calcImprovedShareEnergy)
-			calcProfit2 := roundTo1(getProfit(calcW3, costElectricity))
-			calcW4 := getW2(averageCapacity, calcImprovedShareEnergy)
-			calcFine2 := getFine(calcW4, costElectricity)
-			calcMainProfit := roundTo1(getMainProfit(calcProfit2, calcFine2))
-
+		if in, ok := readDaily(r, &data); ok {
 			data.HasResult = true
-			data.ShareEnergy = calcShareEnergy
-			data.W1 = calcW1
-			data.Profit1 = calcProfit1
-			data.W2 = calcW2
-			data.Fine1 = calcFine1
-			data.ImprovedShareEnergy = calcImprovedShareEnergy
-			data.W3 = calcW3
-			data.Profit2 = calcProfit2
-			data.W4 = calcW4
-			data.Fine2 = calcFine2
-			data.MainProfit = calcMainProfit
+			data.LowerBound, data.UpperBound = toleranceBand(in.capacity, in.tolerance)
+			data.Current = calculateDaily(in.capacity, in.sigma, in.tolerance, in.prices)
+			data.Improved = calculateDaily(in.capacity, in.oversight, in.tolerance, in.prices)
+			data.MainProfit = data.Improved.Profit
 		}
 	}
 
-	err := pageTmpl.Execute(w, data)
-	if err != nil {
-		http.Error(w, "Template error", http.StatusInternalServerError)
+	render(w, data)
+}
+
+// dailyInput — розібрані поля добової форми.
+type dailyInput struct {
+	capacity, sigma, oversight, tolerance float64
+	prices                                Prices
+}
+
+// readDaily зчитує добову форму в data; за помилки заповнює data.Error.
+func readDaily(r *http.Request, data *PageData) (dailyInput, bool) {
+	data.AverageDailyCapacity = r.FormValue("average-daily-capacity")
+	data.MeanSquareDeviation = r.FormValue("mean-square-deviation")
+	data.Oversight = r.FormValue("oversight")
+	data.Tolerance = r.FormValue("tolerance")
+	if data.Tolerance == "" {
+		data.Tolerance = formatFloat(defaultTolerance)
 	}
+	data.SigmaFrom = r.FormValue("sigma-from")
+	data.SigmaTo = r.FormValue("sigma-to")
+	data.AnnualCost = r.FormValue("annual-cost")
+	data.DaysPerYear = r.FormValue("days-per-year")
+
+	var in dailyInput
+	var err1, err2, err3, err4, err error
+	in.capacity, err1 = parseFinite(data.AverageDailyCapacity)
+	in.sigma, err2 = parseFinite(data.MeanSquareDeviation)
+	in.oversight, err3 = parseFinite(data.Oversight)
+	in.tolerance, err4 = parseFinite(data.Tolerance)
+	data.Prices, in.prices, err = readPrices(r)
+
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		data.Error = "Будь ласка, введіть коректні числові значення."
+	} else if err != nil {
+		data.Error = err.Error()
+	} else if in.capacity <= 0 || in.sigma <= 0 || in.oversight <= 0 {
+		data.Error = "Потужність і середньоквадратичні відхилення мають бути більшими за нуль."
+	} else if in.tolerance <= 0 || in.tolerance >= 100 {
+		data.Error = "Допустиме відхилення має бути від 0 до 100 %."
+	}
+	return in, data.Error == ""
 }
 
-func getShareEnergy(averageCapacity, meanSquareDev float64) int {
-	lowerBound := 4.75
-	upperBound := 5.25
+// parseFinite розбирає числове поле форми; NaN і нескінченність, які
+// strconv.ParseFloat приймає, вважаються некоректними значеннями.
+func parseFinite(value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return 0, fmt.Errorf("%q не є скінченним числом", value)
+	}
+	return v, err
+}
 
-	a := erf((upperBound - averageCapacity) / (math.Sqrt(2) * meanSquareDev))
-	b := erf((lowerBound - averageCapacity) / (math.Sqrt(2) * meanSquareDev))
+// optionalFloat розбирає необов'язкове поле: порожнє — значення за замовчуванням.
+func optionalFloat(value string, def float64) (float64, error) {
+	if value == "" {
+		return def, nil
+	}
+	return parseFinite(value)
+}
 
-	result := 0.5 * (a - b)
+// sweepPage — залежність прибутку від σ та окупність системи прогнозування.
+func sweepPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	data := PageData{}
+	in, ok := readDaily(r, &data)
+	if !ok {
+		render(w, data)
+		return
+	}
 
-	return int(math.Round(result))
+	from, err1 := optionalFloat(data.SigmaFrom, math.Min(0.05, in.oversight))
+	to, err2 := optionalFloat(data.SigmaTo, in.sigma)
+	annualCost, err3 := parseFinite(data.AnnualCost)
+	days, err4 := optionalFloat(data.DaysPerYear, defaultDaysPerYear)
+	switch {
+	case err1 != nil || err2 != nil || err3 != nil || err4 != nil:
+		data.Error = "Вкажіть коректні межі σ, річну вартість системи та кількість днів."
+	case from <= 0 || to <= from:
+		data.Error = "Межі σ мають бути додатними, а σ до — більшою за σ від."
+	case annualCost < 0:
+		data.Error = "Річна вартість системи не може бути від'ємною."
+	case days <= 0 || days > 366:
+		data.Error = "Кількість днів роботи має бути від 1 до 366."
+	default:
+		res := sweepSigma(in.capacity, in.sigma, in.oversight, from, to, defaultSweepPoints, in.tolerance, in.prices, annualCost, days)
+		data.Sweep = &res
+		data.SweepChart = template.HTML(sweepChart(res))
+	}
+	render(w, data)
 }
 
-func erf(x float64) float64 {
-	p := 0.3275911
-	a1 := 0.254829592
-	a2 := -0.284496736
-	a3 := 1.421413741
-	a4 := -1.453152027
-	a5 := 1.061405429
+// portfolioPage — розрахунок портфеля станцій; рядки без потужності пропускаються.
+func portfolioPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	_ = r.ParseForm()
+	data := PageData{
+		PlantRows:          []PlantRow{},
+		Correlation:        r.FormValue("correlation"),
+		PortfolioTolerance: r.FormValue("tolerance"),
+		PortfolioPrices:    PriceForm{Surplus: r.FormValue("price-surplus"), Deficit: r.FormValue("price-deficit")},
+		DaysPerMonth:       r.FormValue("days-per-month"),
+	}
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
 
-	sign := 1.0
-	if x < 0 {
-		sign = -1.0
+	var plants []Plant
+	for i := range r.Form["plant-capacity"] {
+		row := PlantRow{
+			Name:     at(r.Form["plant-name"], i),
+			Capacity: at(r.Form["plant-capacity"], i),
+			Sigma:    at(r.Form["plant-sigma"], i),
+			Tariff:   at(r.Form["plant-tariff"], i),
+		}
+		if row.Capacity == "" {
+			continue
+		}
+		data.PlantRows = append(data.PlantRows, row)
+		if row.Name == "" {
+			row.Name = fmt.Sprintf("Станція %d", len(data.PlantRows))
+		}
+		p := Plant{Name: row.Name}
+		var err1, err2, err3 error
+		p.Capacity, err1 = parseFinite(row.Capacity)
+		p.Sigma, err2 = parseFinite(row.Sigma)
+		p.Tariff, err3 = parseFinite(row.Tariff)
+		if data.PortfolioError == "" {
+			if err1 != nil || err2 != nil || err3 != nil {
+				data.PortfolioError = fmt.Sprintf("%s: введіть коректні числові значення.", row.Name)
+			} else if p.Capacity <= 0 || p.Sigma <= 0 || p.Tariff < 0 {
+				data.PortfolioError = fmt.Sprintf("%s: потужність і σ мають бути більшими за нуль, тариф — невід'ємним.", row.Name)
+			}
+		}
+		plants = append(plants, p)
 	}
-	x = math.Abs(x)
 
-	t := 1.0 / (1.0 + p*x)
-	y := 1.0 - (((((a5*t+a4)*t)+a3)*t+a2)*t+a1)*t*math.Exp(-x*x)
+	correlation, err1 := optionalFloat(data.Correlation, exampleCorrelation)
+	tolerance, err2 := optionalFloat(data.PortfolioTolerance, defaultTolerance)
+	imbalance, err3 := readImbalancePrices(data.PortfolioPrices)
+	days, err4 := optionalFloat(data.DaysPerMonth, defaultDaysPerMonth)
+	switch {
+	case data.PortfolioError != "":
+	case err1 != nil || err2 != nil || err4 != nil:
+		data.PortfolioError = "Будь ласка, введіть коректні числові значення."
+	case err3 != nil:
+		data.PortfolioError = err3.Error()
+	case tolerance <= 0 || tolerance >= 100:
+		data.PortfolioError = "Допустиме відхилення має бути від 0 до 100 %."
+	case days <= 0 || days > 31:
+		data.PortfolioError = "Кількість днів у місяці має бути від 1 до 31."
+	default:
+		res, err := calculatePortfolio(plants, correlation, tolerance, imbalance, days)
+		if err != nil {
+			data.PortfolioError = err.Error()
+		} else {
+			data.Portfolio = &res
+		}
+	}
+	render(w, data)
+}
 
-	return sign * y * 100
+// hourlyPage — розрахунок за інтервалами; графік береться з файлу, якщо
+// його вибрано, інакше з текстового поля.
+func hourlyPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	data := PageData{}
+	var err error
+	if data.HourlyProfile, err = readUpload(r, "profile", "profile-file"); err != nil {
+		data.HourlyError = err.Error()
+		render(w, data)
+		return
+	}
+	data.HourlyTolerance = r.FormValue("tolerance")
+
+	tolerance, err1 := parseFinite(data.HourlyTolerance)
+	var prices Prices
+	var err2 error
+	data.HourlyPrices, prices, err2 = readPrices(r)
+	declared, actual, err := parseProfile(data.HourlyProfile)
+	switch {
+	case err1 != nil:
+		data.HourlyError = "Будь ласка, введіть коректні числові значення."
+	case err2 != nil:
+		data.HourlyError = err2.Error()
+	case tolerance <= 0 || tolerance >= 100:
+		data.HourlyError = "Допустиме відхилення має бути від 0 до 100 %."
+	case err != nil:
+		data.HourlyError = err.Error()
+	default:
+		res := calculateHourly(declared, actual, prices, tolerance)
+		data.Hourly = &res
+		data.HourlyChart = template.HTML(hourlyChart(res, tolerance))
+	}
+	render(w, data)
 }
 
-func getW1(averageCapacity float64, calcShareEnergy int) float64 {
-	return (averageCapacity * 24 * float64(calcShareEnergy)) / 100
+// readUpload повертає вміст вибраного файлу або, якщо файл не вибрано,
+// текстового поля.
+func readUpload(r *http.Request, textField, fileField string) (string, error) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+		return "", fmt.Errorf("Не вдалося прочитати форму: %w", err)
+	}
+	file, _, err := r.FormFile(fileField)
+	if err != nil {
+		return r.FormValue(textField), nil
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("Не вдалося прочитати файл: %w", err)
+	}
+	return string(content), nil
+}
+
+// runEstimate оцінює похибку за історією та розраховує добовий прибуток з
+// оціненими параметрами.
+func runEstimate(history string, tolerance float64, prices Prices) (ErrorEstimate, DailyResult, DailyResult, error) {
+	forecast, actual, err := parsePairs(history)
+	if err != nil {
+		return ErrorEstimate{}, DailyResult{}, DailyResult{}, err
+	}
+	est, err := estimateErrors(forecast, actual, tolerance)
+	if err != nil {
+		return est, DailyResult{}, DailyResult{}, err
+	}
+	biased := calculateDailyBiased(est.MeanForecast, est.Bias, est.Sigma, tolerance, prices)
+	unbiased := calculateDaily(est.MeanForecast, est.Sigma, tolerance, prices)
+	return est, biased, unbiased, nil
+}
+
+// estimatePage — оцінка похибки з форми; оцінені Pc і σ підставляються
+// в добову форму.
+func estimatePage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	data := PageData{}
+	var err error
+	if data.History, err = readUpload(r, "history", "history-file"); err != nil {
+		data.EstimateError = err.Error()
+		render(w, data)
+		return
+	}
+	data.EstimateTolerance = r.FormValue("tolerance")
+
+	tolerance, err1 := parseFinite(data.EstimateTolerance)
+	var prices Prices
+	var err2 error
+	data.EstimatePrices, prices, err2 = readPrices(r)
+	switch {
+	case err1 != nil:
+		data.EstimateError = "Будь ласка, введіть коректні числові значення."
+	case err2 != nil:
+		data.EstimateError = err2.Error()
+	case tolerance <= 0 || tolerance >= 100:
+		data.EstimateError = "Допустиме відхилення має бути від 0 до 100 %."
+	default:
+		est, biased, unbiased, err := runEstimate(data.History, tolerance, prices)
+		if err != nil {
+			data.EstimateError = err.Error()
+			break
+		}
+		data.Estimate = &est
+		data.EstimateDaily = &biased
+		data.EstimateUnbiased = &unbiased
+		data.EstimateChart = template.HTML(errorChart(est))
+
+		data.AverageDailyCapacity = strconv.FormatFloat(est.MeanForecast, 'f', 3, 64)
+		data.MeanSquareDeviation = strconv.FormatFloat(est.Sigma, 'f', 4, 64)
+		data.Prices = data.EstimatePrices
+		data.Tolerance = data.EstimateTolerance
+	}
+	render(w, data)
+}
+
+// apiEstimate — оцінка для API-клієнтів: тіло запиту — CSV, параметри
+// tolerance, cost-electricity, price-surplus, price-deficit — у рядку запиту.
+// Прибуток розраховується, якщо задано тариф.
+func apiEstimate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fail := func(status int, err error) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	}
+	if r.Method != http.MethodPost {
+		fail(http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		fail(http.StatusBadRequest, err)
+		return
+	}
+	q := r.URL.Query()
+	tolerance, err := optionalFloat(q.Get("tolerance"), defaultTolerance)
+	if err != nil || tolerance <= 0 || tolerance >= 100 {
+		fail(http.StatusBadRequest, errors.New("tolerance має бути числом від 0 до 100"))
+		return
+	}
+
+	var resp struct {
+		Estimate ErrorEstimate `json:"estimate"`
+		Daily    *DailyResult  `json:"daily,omitempty"`
+		Unbiased *DailyResult  `json:"unbiased,omitempty"`
+	}
+	if q.Get("cost-electricity") == "" {
+		forecast, actual, err := parsePairs(string(body))
+		if err == nil {
+			resp.Estimate, err = estimateErrors(forecast, actual, tolerance)
+		}
+		if err != nil {
+			fail(http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		_, prices, err := readPrices(r)
+		if err != nil {
+			fail(http.StatusBadRequest, err)
+			return
+		}
+		est, biased, unbiased, err := runEstimate(string(body), tolerance, prices)
+		if err != nil {
+			fail(http.StatusBadRequest, err)
+			return
+		}
+		resp.Estimate, resp.Daily, resp.Unbiased = est, &biased, &unbiased
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// defaultTolerance — допустиме відхилення від заявленої потужності, %.
+const defaultTolerance = 5.0
+
+// toleranceBand — межі потужності, в яких енергія не вважається небалансом.
+func toleranceBand(averageCapacity, tolerance float64) (lower, upper float64) {
+	delta := averageCapacity * tolerance / 100
+	return averageCapacity - delta, averageCapacity + delta
+}
+
+// getShareEnergy — частка енергії без небалансів, %: ймовірність того, що
+// потужність з нормальним розподілом N(Pc + bias, σ) потрапить у смугу
+// Pc ± δ, тобто Φ((upper−Pc−bias)/σ) − Φ((lower−Pc−bias)/σ).
+func getShareEnergy(averageCapacity, bias, meanSquareDev, tolerance float64) float64 {
+	lowerBound, upperBound := toleranceBand(averageCapacity, tolerance)
+	mean := averageCapacity + bias
+	return (normalCDF(upperBound, mean, meanSquareDev) - normalCDF(lowerBound, mean, meanSquareDev)) * 100
+}
+
+func normalCDF(x, mean, sigma float64) float64 {
+	return 0.5 * (1 + math.Erf((x-mean)/(sigma*math.Sqrt2)))
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func getW1(averageCapacity, calcShareEnergy float64) float64 {
+	return (averageCapacity * 24 * calcShareEnergy) / 100
 }
 
 func getProfit(w, costElectricity float64) float64 {
 	return w * costElectricity
 }
 
-func getW2(averageCapacity float64, calcShareEnergy int) float64 {
-	return (averageCapacity * 24 * float64(100-calcShareEnergy)) / 100
+func getW2(averageCapacity, calcShareEnergy float64) float64 {
+	return (averageCapacity * 24 * (100 - calcShareEnergy)) / 100
 }
 
 func getFine(w, costElectricity float64) float64 {
@@ -238,7 +871,3 @@ func getFine(w, costElectricity float64) float64 {
 func getMainProfit(profit, fine float64) float64 {
 	return profit - fine
 }
-
-func roundTo1(x float64) float64 {
-	return math.Round(x*10) / 10
-}
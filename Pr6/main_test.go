@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// approx перевіряє абсолютне відхилення не більше tol.
+func approx(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.IsNaN(got) || math.Abs(got-want) > tol {
+		t.Errorf("%s = %.6g, want %.6g (±%g)", name, got, want, tol)
+	}
+}
+
+func TestParseFinite(t *testing.T) {
+	if v, err := parseFinite("2.5"); err != nil || v != 2.5 {
+		t.Errorf("parseFinite(2.5) = %g, %v", v, err)
+	}
+	for _, s := range []string{"", "abc", "NaN", "nan", "Inf", "-Inf", "+Infinity", "1e400"} {
+		if _, err := parseFinite(s); err == nil {
+			t.Errorf("parseFinite(%q): помилки немає", s)
+		}
+	}
+}
+
+func TestNormalCDF(t *testing.T) {
+	for _, tt := range []struct{ x, want float64 }{
+		{0, 0.5}, {1, 0.841345}, {-1, 0.158655}, {1.96, 0.975002}, {0.25, 0.598706},
+	} {
+		approx(t, "Φ", normalCDF(tt.x, 0, 1), tt.want, 1e-6)
+	}
+	approx(t, "N(5, 2)", normalCDF(7, 5, 2), 0.841345, 1e-6)
+}
+
+// Частка енергії без небалансів для прикладу Pc = 5 МВт, δ = 5 %:
+// 2Φ(0,25) − 1 при σ = 1 та 2Φ(1) − 1 при σ = 0,25.
+func TestShareEnergy(t *testing.T) {
+	approx(t, "σ = 1", getShareEnergy(5, 0, 1, 5), 19.7413, 1e-3)
+	approx(t, "σ = 0,25", getShareEnergy(5, 0, 0.25, 5), 68.2689, 1e-3)
+	approx(t, "зміщення", getShareEnergy(5, 0.25, 0.25, 5), (normalCDF(0, 0, 1)-normalCDF(-2, 0, 1))*100, 1e-9)
+}
+
+// Добова форма не приймає NaN та нескінченність.
+func TestReadDailyRejectsNonFinite(t *testing.T) {
+	valid := url.Values{
+		"average-daily-capacity": {"5"},
+		"mean-square-deviation":  {"1"},
+		"oversight":              {"0.25"},
+		"tolerance":              {"5"},
+		"cost-electricity":       {"7"},
+	}
+	for _, field := range []string{"average-daily-capacity", "mean-square-deviation", "oversight", "tolerance", "cost-electricity", "price-surplus", "price-deficit"} {
+		for _, bad := range []string{"NaN", "Inf", "-Inf"} {
+			form := url.Values{}
+			for k, v := range valid {
+				form[k] = v
+			}
+			form.Set(field, bad)
+			r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			var data PageData
+			if _, ok := readDaily(r, &data); ok {
+				t.Errorf("%s = %s прийнято", field, bad)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"net/http"
+)
+
+// Ціни балансуючого ринку. Енергія в межах смуги допуску продається за
+// тарифом. Енергія інтервалів з небалансом врегульовується несиметрично:
+// профіцит (фактична генерація вища за смугу) продається за ціною
+// позитивного небалансу, за дефіцит (нижча) станція платить ціну
+// негативного небалансу.
+//
+// Знаки: тариф і ціна дефіциту — невід'ємні, ціна профіциту може мати
+// будь-який знак — додатна означає, що станція отримує гроші за профіцит,
+// від'ємна — що платить за нього. Якщо ціни небалансу не задано, профіцит
+// коштує мінус тариф, а дефіцит — тариф: за обидва небаланси станція
+// платить тариф, як у початковій методиці.
+
+type Prices struct {
+	Sale    float64 // тариф продажу, грн/кВт*год
+	Surplus float64 // ціна позитивного небалансу, грн/кВт*год; від'ємна — плата станції
+	Deficit float64 // ціна негативного небалансу, грн/кВт*год, платить станція
+}
+
+// Settlement — розрахунки за енергію, тис. грн.
+type Settlement struct {
+	Sales      float64 `json:"sales"`      // енергія в смузі за тарифом
+	SurplusPay float64 `json:"surplusPay"` // профіцит за ціною профіциту; від'ємна — платіж станції
+	DeficitPay float64 `json:"deficitPay"` // платіж станції за дефіцит
+}
+
+// settle рахує розрахунки за енергію в смузі, з профіцитом і з дефіцитом, МВт*год.
+func (p Prices) settle(within, surplus, deficit float64) Settlement {
+	return Settlement{
+		Sales:      getProfit(within, p.Sale),
+		SurplusPay: getProfit(surplus, p.Surplus),
+		DeficitPay: getFine(deficit, p.Deficit),
+	}
+}
+
+// income — надходження: продаж і додатна оплата профіциту.
+func (s Settlement) income() float64 {
+	return s.Sales + math.Max(s.SurplusPay, 0)
+}
+
+// charges — платежі станції: дефіцит і профіцит за від'ємною ціною.
+func (s Settlement) charges() float64 {
+	return s.DeficitPay + math.Max(-s.SurplusPay, 0)
+}
+
+// PriceForm — поля цін, значення як введено.
+type PriceForm struct {
+	Sale, Surplus, Deficit string
+}
+
+// ImbalancePrices — задані ціни небалансу; nil — незадана ціна, що
+// береться від тарифу (див. forTariff).
+type ImbalancePrices struct {
+	Surplus, Deficit *float64
+}
+
+// forTariff доповнює ціни небалансу тарифом sale: профіцит за замовчуванням
+// коштує −sale, дефіцит — sale.
+func (ip ImbalancePrices) forTariff(sale float64) Prices {
+	p := Prices{Sale: sale, Surplus: -sale, Deficit: sale}
+	if ip.Surplus != nil {
+		p.Surplus = *ip.Surplus
+	}
+	if ip.Deficit != nil {
+		p.Deficit = *ip.Deficit
+	}
+	return p
+}
+
+// readImbalancePrices розбирає ціни небалансу з форми; порожнє поле — nil.
+func readImbalancePrices(f PriceForm) (ImbalancePrices, error) {
+	var ip ImbalancePrices
+	if f.Surplus != "" {
+		v, err := parseFinite(f.Surplus)
+		if err != nil {
+			return ip, errors.New("Будь ласка, введіть коректну ціну позитивного небалансу.")
+		}
+		ip.Surplus = &v
+	}
+	if f.Deficit != "" {
+		v, err := parseFinite(f.Deficit)
+		if err != nil {
+			return ip, errors.New("Будь ласка, введіть коректну ціну негативного небалансу.")
+		}
+		if v < 0 {
+			return ip, errors.New("Ціна дефіциту не може бути від'ємною.")
+		}
+		ip.Deficit = &v
+	}
+	return ip, nil
+}
+
+func readPrices(r *http.Request) (PriceForm, Prices, error) {
+	f := PriceForm{
+		Sale:    r.FormValue("cost-electricity"),
+		Surplus: r.FormValue("price-surplus"),
+		Deficit: r.FormValue("price-deficit"),
+	}
+	sale, err := parseFinite(f.Sale)
+	if err != nil {
+		return f, Prices{}, errors.New("Будь ласка, введіть коректний тариф продажу.")
+	}
+	if sale < 0 {
+		return f, Prices{}, errors.New("Тариф не може бути від'ємним.")
+	}
+	ip, err := readImbalancePrices(f)
+	if err != nil {
+		return f, Prices{}, err
+	}
+	return f, ip.forTariff(sale), nil
+}
+
+// DailyResult — добовий розрахунок за середньою потужністю Pc і σ прогнозу.
+type DailyResult struct {
+	Sigma        float64 `json:"sigma"`
+	Share        float64 `json:"share"`        // частка енергії без небалансів, %
+	SurplusShare float64 `json:"surplusShare"` // частка з профіцитом, %
+	DeficitShare float64 `json:"deficitShare"` // частка з дефіцитом, %
+
+	Within  float64 `json:"within"`  // W без небалансів, МВт*год
+	Outside float64 `json:"outside"` // W з небалансами, МВт*год
+	Surplus float64 `json:"surplus"` // з них профіцит, МВт*год
+	Deficit float64 `json:"deficit"` // з них дефіцит, МВт*год
+
+	Settlement
+	Revenue float64 `json:"revenue"` // надходження, тис. грн
+	Fine    float64 `json:"fine"`    // платежі за небаланси, тис. грн
+	Profit  float64 `json:"profit"`  // надходження мінус платежі, тис. грн
+}
+
+func calculateDaily(averageCapacity, meanSquareDev, tolerance float64, prices Prices) DailyResult {
+	return calculateDailyBiased(averageCapacity, 0, meanSquareDev, tolerance, prices)
+}
+
+// calculateDailyBiased — те саме для прогнозу із систематичною похибкою:
+// фактична потужність має розподіл N(Pc + bias, σ), смуга — від Pc.
+func calculateDailyBiased(averageCapacity, bias, meanSquareDev, tolerance float64, prices Prices) DailyResult {
+	res := DailyResult{Sigma: meanSquareDev}
+	lower, upper := toleranceBand(averageCapacity, tolerance)
+	mean := averageCapacity + bias
+	res.SurplusShare = (1 - normalCDF(upper, mean, meanSquareDev)) * 100
+	res.DeficitShare = normalCDF(lower, mean, meanSquareDev) * 100
+	res.Share = getShareEnergy(averageCapacity, bias, meanSquareDev, tolerance)
+
+	res.Within = getW1(averageCapacity, res.Share)
+	res.Outside = getW2(averageCapacity, res.Share)
+	res.Surplus = getW1(averageCapacity, res.SurplusShare)
+	res.Deficit = getW1(averageCapacity, res.DeficitShare)
+
+	res.Settlement = prices.settle(res.Within, res.Surplus, res.Deficit)
+	res.Revenue = res.income()
+	res.Fine = res.charges()
+	res.Profit = getMainProfit(res.Revenue, res.Fine)
+	return res
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// З цінами за замовчуванням розрахунок збігається з початковою методикою:
+// надходження — енергія в смузі за тарифом, штраф — енергія поза смугою за тарифом.
+func TestSettleLegacy(t *testing.T) {
+	prices := Prices{Sale: 7, Surplus: -7, Deficit: 7}
+	for _, tt := range []struct {
+		sigma, revenue, fine float64
+	}{
+		{1, 165.83, 674.17},
+		{0.25, 573.46, 266.54},
+	} {
+		d := calculateDaily(5, tt.sigma, 5, prices)
+		legacyRevenue := getProfit(getW1(5, d.Share), 7)
+		legacyFine := getFine(getW2(5, d.Share), 7)
+		approx(t, "надходження", d.Revenue, legacyRevenue, 1e-9)
+		approx(t, "штраф", d.Fine, legacyFine, 1e-9)
+		approx(t, "надходження (приклад)", d.Revenue, tt.revenue, 0.01)
+		approx(t, "штраф (приклад)", d.Fine, tt.fine, 0.01)
+		approx(t, "профіцит + дефіцит", d.Surplus+d.Deficit, d.Outside, 1e-9)
+	}
+}
+
+func TestSettleSigns(t *testing.T) {
+	s := Prices{Sale: 7, Surplus: 2, Deficit: 9}.settle(10, 3, 4)
+	approx(t, "продаж", s.Sales, 70, 1e-9)
+	approx(t, "профіцит", s.SurplusPay, 6, 1e-9)
+	approx(t, "дефіцит", s.DeficitPay, 36, 1e-9)
+	approx(t, "надходження", s.income(), 76, 1e-9)
+	approx(t, "платежі", s.charges(), 36, 1e-9)
+
+	s = Prices{Sale: 7, Surplus: -1, Deficit: 9}.settle(10, 3, 4)
+	approx(t, "надходження (від'ємна ціна)", s.income(), 70, 1e-9)
+	approx(t, "платежі (від'ємна ціна)", s.charges(), 39, 1e-9)
+}
+
+func TestReadPrices(t *testing.T) {
+	for _, tt := range []struct {
+		surplus, deficit string
+		want             Prices
+		ok               bool
+	}{
+		{"", "", Prices{7, -7, 7}, true},
+		{"2", "9", Prices{7, 2, 9}, true},
+		{"-1.5", "", Prices{7, -1.5, 7}, true},
+		{"", "-1", Prices{}, false},
+		{"NaN", "", Prices{}, false},
+	} {
+		form := url.Values{"cost-electricity": {"7"}, "price-surplus": {tt.surplus}, "price-deficit": {tt.deficit}}
+		r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		_, p, err := readPrices(r)
+		if (err == nil) != tt.ok || (tt.ok && p != tt.want) {
+			t.Errorf("профіцит %q, дефіцит %q: %+v, %v", tt.surplus, tt.deficit, p, err)
+		}
+	}
+}
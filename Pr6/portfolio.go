@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Портфель сонячних станцій. Кожна станція розраховується окремо за
+// власними Pc, σ і тарифом, як у добовому розрахунку. Для портфеля,
+// що врегульовує небаланси як одна балансуюча група, похибки прогнозу
+// станцій підсумовуються: сумарна потужність має нормальний розподіл
+// N(ΣPc, σΣ), де σΣ² = Σσi² + 2ρ·Σi<j σi·σj при однаковій кореляції ρ
+// між похибками будь-яких двох станцій. Смуга допуску — від ΣPc, ціни —
+// середні, зважені за потужністю, тож виручка дорівнює Σ частка·Pc,i·24·Вi.
+
+const defaultDaysPerMonth = 30
+
+type Plant struct {
+	Name     string
+	Capacity float64 // Pc, МВт
+	Sigma    float64 // σ, МВт
+	Tariff   float64 // грн/кВт*год
+}
+
+// Приклад: три станції з різною потужністю, точністю прогнозу та тарифом.
+var examplePlants = []Plant{
+	{Name: "СЕС-1", Capacity: 5, Sigma: 1, Tariff: 7},
+	{Name: "СЕС-2", Capacity: 3, Sigma: 0.6, Tariff: 7.5},
+	{Name: "СЕС-3", Capacity: 8, Sigma: 1.5, Tariff: 6.5},
+}
+
+const exampleCorrelation = 0.5
+
+// Totals — виручка, штрафи та прибуток за період, тис. грн.
+type Totals struct {
+	Revenue, Fine, Profit float64
+}
+
+func (d DailyResult) Totals() Totals {
+	return Totals{Revenue: d.Revenue, Fine: d.Fine, Profit: d.Profit}
+}
+
+func (t Totals) add(o Totals) Totals {
+	return Totals{t.Revenue + o.Revenue, t.Fine + o.Fine, t.Profit + o.Profit}
+}
+
+func (t Totals) scale(k float64) Totals {
+	return Totals{t.Revenue * k, t.Fine * k, t.Profit * k}
+}
+
+type PlantResult struct {
+	Plant
+	Daily DailyResult
+	Month Totals
+}
+
+type PortfolioResult struct {
+	Plants      []PlantResult
+	Capacity    float64 // ΣPc, МВт
+	Sigma       float64 // σΣ, МВт
+	SigmaSum    float64 // Σσi — σΣ при ρ = 1
+	Correlation float64
+	Days        float64 // днів у місяці
+
+	Standalone      Totals // сума окремих розрахунків за добу
+	StandaloneMonth Totals
+	Aggregate       DailyResult // балансуюча група за добу
+	AggregateMonth  Totals
+
+	Benefit      float64 // прибуток групи мінус сума окремих, тис. грн за добу
+	BenefitMonth float64
+}
+
+// minCorrelation — найменша ρ, за якої матриця однакових кореляцій n
+// станцій невід'ємно визначена.
+func minCorrelation(n int) float64 {
+	if n < 2 {
+		return -1
+	}
+	return -1 / float64(n-1)
+}
+
+// calculatePortfolio; незадані ціни небалансу беруться від тарифу станції.
+func calculatePortfolio(plants []Plant, correlation, tolerance float64, imbalance ImbalancePrices, days float64) (PortfolioResult, error) {
+	res := PortfolioResult{Correlation: correlation, Days: days}
+	if len(plants) == 0 {
+		return res, errors.New("Портфель не містить жодної станції.")
+	}
+	if lo := minCorrelation(len(plants)); correlation < lo || correlation > 1 {
+		return res, fmt.Errorf("Для %d станцій кореляція має бути від %.3g до 1.", len(plants), lo)
+	}
+
+	var weighted Prices
+	variance := 0.0
+	for i, p := range plants {
+		prices := imbalance.forTariff(p.Tariff)
+		daily := calculateDaily(p.Capacity, p.Sigma, tolerance, prices)
+		res.Plants = append(res.Plants, PlantResult{Plant: p, Daily: daily, Month: daily.Totals().scale(days)})
+		res.Standalone = res.Standalone.add(daily.Totals())
+
+		res.Capacity += p.Capacity
+		res.SigmaSum += p.Sigma
+		weighted.Sale += prices.Sale * p.Capacity
+		weighted.Surplus += prices.Surplus * p.Capacity
+		weighted.Deficit += prices.Deficit * p.Capacity
+
+		variance += p.Sigma * p.Sigma
+		for _, q := range plants[:i] {
+			variance += 2 * correlation * p.Sigma * q.Sigma
+		}
+	}
+	weighted.Sale /= res.Capacity
+	weighted.Surplus /= res.Capacity
+	weighted.Deficit /= res.Capacity
+	res.Sigma = math.Sqrt(math.Max(variance, 0))
+
+	res.StandaloneMonth = res.Standalone.scale(days)
+	if res.Sigma > 0 {
+		res.Aggregate = calculateDaily(res.Capacity, res.Sigma, tolerance, weighted)
+	} else {
+		// похибки повністю компенсують одна одну — небалансів немає
+		res.Aggregate = DailyResult{Share: 100, Within: getW1(res.Capacity, 100)}
+		res.Aggregate.Settlement = weighted.settle(res.Aggregate.Within, 0, 0)
+		res.Aggregate.Revenue = res.Aggregate.income()
+		res.Aggregate.Profit = res.Aggregate.Revenue
+	}
+	res.AggregateMonth = res.Aggregate.Totals().scale(days)
+	res.Benefit = res.Aggregate.Profit - res.Standalone.Profit
+	res.BenefitMonth = res.Benefit * days
+	return res, nil
+}
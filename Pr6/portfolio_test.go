@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestPortfolioExample(t *testing.T) {
+	res, err := calculatePortfolio(examplePlants, exampleCorrelation, defaultTolerance, ImbalancePrices{}, defaultDaysPerMonth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx(t, "σΣ", res.Sigma, 2.571, 0.001)
+	approx(t, "ефект", res.Benefit, 214.46, 0.01)
+	approx(t, "ефект за місяць", res.BenefitMonth, res.Benefit*defaultDaysPerMonth, 1e-9)
+	for _, p := range res.Plants {
+		if p.Daily.Settlement != (Prices{Sale: p.Tariff, Surplus: -p.Tariff, Deficit: p.Tariff}).settle(p.Daily.Within, p.Daily.Surplus, p.Daily.Deficit) {
+			t.Errorf("%s: ціни за замовчуванням не від тарифу станції", p.Name)
+		}
+	}
+}
+
+// Від'ємна ціна профіциту дозволена, як і в добовому розрахунку.
+func TestPortfolioImbalancePrices(t *testing.T) {
+	ip, err := readImbalancePrices(PriceForm{Surplus: "-1", Deficit: "9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := calculatePortfolio(examplePlants, exampleCorrelation, defaultTolerance, ip, defaultDaysPerMonth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range res.Plants {
+		want := Prices{Sale: p.Tariff, Surplus: -1, Deficit: 9}.settle(p.Daily.Within, p.Daily.Surplus, p.Daily.Deficit)
+		if p.Daily.Settlement != want {
+			t.Errorf("%s: %+v, want %+v", p.Name, p.Daily.Settlement, want)
+		}
+	}
+
+	for _, f := range []PriceForm{{Deficit: "-1"}, {Surplus: "NaN"}, {Deficit: "Inf"}, {Surplus: "abc"}} {
+		if _, err := readImbalancePrices(f); err == nil {
+			t.Errorf("%+v прийнято", f)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import "math"
+
+// Окупність системи прогнозування: добовий прибуток розраховується для
+// ряду значень σ, приріст відносно поточної σ0 переводиться в річний і
+// порівнюється з річною вартістю системи. За несиметричних цін небалансу
+// прибуток не обов'язково спадає зі зростанням σ, тому σ беззбитковості
+// шукається на розрахованій кривій: перша пара сусідніх точок, між якими
+// (P(σ) − P(σ0))·днів − вартість змінює знак з невід'ємного на від'ємний,
+// уточнюється поділом навпіл. Якщо такої пари немає, точки беззбитковості
+// на відрізку σ немає.
+
+const (
+	defaultSweepPoints = 40
+	defaultDaysPerYear = 365
+)
+
+type SweepPoint struct {
+	Sigma   float64
+	Share   float64 // %
+	Profit  float64 // добовий прибуток, тис. грн
+	Gain    float64 // річний приріст прибутку відносно σ0, тис. грн
+	Net     float64 // річний приріст мінус вартість системи, тис. грн
+	Payback float64 // днів роботи, за які окупається річна вартість; +Inf — не окупається
+}
+
+type SweepResult struct {
+	Current    DailyResult
+	Target     SweepPoint // для σ, до якої планується зменшити похибку
+	Points     []SweepPoint
+	AnnualCost float64 // тис. грн/рік
+	Days       float64
+
+	BreakEven    float64 // σ, за якої приріст дорівнює вартості, на відрізку Points
+	HasBreakEven bool
+}
+
+func sweepSigma(averageCapacity, sigma0, target, from, to float64, points int, tolerance float64, prices Prices, annualCost, days float64) SweepResult {
+	res := SweepResult{
+		Current:    calculateDaily(averageCapacity, sigma0, tolerance, prices),
+		AnnualCost: annualCost,
+		Days:       days,
+	}
+	point := func(sigma float64) SweepPoint {
+		d := calculateDaily(averageCapacity, sigma, tolerance, prices)
+		p := SweepPoint{Sigma: sigma, Share: d.Share, Profit: d.Profit}
+		daily := d.Profit - res.Current.Profit
+		p.Gain = daily * days
+		p.Net = p.Gain - annualCost
+		p.Payback = math.Inf(1)
+		if daily > 0 {
+			p.Payback = annualCost / daily
+		}
+		return p
+	}
+
+	for i := 0; i < points; i++ {
+		sigma := from
+		if points > 1 {
+			sigma = from + (to-from)*float64(i)/float64(points-1)
+		}
+		res.Points = append(res.Points, point(sigma))
+	}
+	res.Target = point(target)
+
+	for i := 1; i < len(res.Points); i++ {
+		if res.Points[i-1].Net < 0 || res.Points[i].Net >= 0 {
+			continue
+		}
+		lo, hi := res.Points[i-1].Sigma, res.Points[i].Sigma
+		for j := 0; j < 100; j++ {
+			mid := (lo + hi) / 2
+			if point(mid).Net >= 0 {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		res.BreakEven, res.HasBreakEven = lo, true
+		break
+	}
+	return res
+}
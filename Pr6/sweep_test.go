@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestSweepBreakEven(t *testing.T) {
+	prices := Prices{Sale: 7, Surplus: -7, Deficit: 7}
+	res := sweepSigma(5, 1, 0.25, 0.05, 1, defaultSweepPoints, 5, prices, 50000, defaultDaysPerYear)
+	if !res.HasBreakEven {
+		t.Fatal("точку беззбитковості не знайдено")
+	}
+	approx(t, "σ беззбитковості", res.BreakEven, 0.7002, 1e-4)
+	d := calculateDaily(5, res.BreakEven, 5, prices)
+	approx(t, "приріст у точці", (d.Profit-res.Current.Profit)*defaultDaysPerYear, 50000, 1e-3)
+	approx(t, "окупність цілі", res.Target.Payback, 61.3, 0.05)
+	if first, last := res.Points[0], res.Points[len(res.Points)-1]; first.Sigma != 0.05 || last.Sigma != 1 || last.Gain != 0 {
+		t.Errorf("межі кривої: %+v … %+v", first, last)
+	}
+
+	// вартість більша за приріст на всьому відрізку — беззбитковості немає
+	res = sweepSigma(5, 1, 0.25, 0.05, 1, defaultSweepPoints, 5, prices, 1e7, defaultDaysPerYear)
+	if res.HasBreakEven {
+		t.Errorf("беззбитковість %v при σ = %g", res.HasBreakEven, res.BreakEven)
+	}
+}
+
+// Якщо прибуток зростає зі зростанням σ (профіцит дорожчий за тариф),
+// приріст від зменшення похибки від'ємний і точки беззбитковості немає.
+func TestSweepNoSignChange(t *testing.T) {
+	prices := Prices{Sale: 1, Surplus: 20, Deficit: 0}
+	res := sweepSigma(5, 1, 0.25, 0.05, 1, defaultSweepPoints, 5, prices, 10, defaultDaysPerYear)
+	for _, p := range res.Points {
+		if p.Net >= 0 {
+			t.Fatalf("σ = %g: приріст %g не від'ємний", p.Sigma, p.Net)
+		}
+	}
+	if res.HasBreakEven {
+		t.Errorf("знайдено беззбитковість при σ = %g", res.BreakEven)
+	}
+}